@@ -7,8 +7,11 @@ toolchain go1.23.5
 require (
 	github.com/mark3labs/mcp-go v0.11.2
 	github.com/ucloud/ucloud-sdk-go v0.22.31
+	gopkg.in/yaml.v3 v3.0.1
 )
 
+require github.com/BurntSushi/toml v1.4.0
+
 require (
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/gorilla/mux v1.8.1
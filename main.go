@@ -1,9 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
+	"io/fs"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/ucloud/ucloud-mcp-server/pkg/config"
 	"github.com/ucloud/ucloud-mcp-server/pkg/mcp"
@@ -12,44 +24,296 @@ import (
 
 func main() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "validate-config" {
+		os.Exit(validateConfig(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "approval" {
+		os.Exit(approval(os.Args[2:]))
+	}
+
 	log.Println("Starting UCloud MCP Server...")
 
 	// Define command line flags
 	configPath := flag.String("config", "config.json", "Path to configuration file")
 	port := flag.String("port", "8080", "Port to listen on")
+	profile := flag.String("profile", "", "Name of the credential profile to use by default")
+	reloadInterval := flag.Duration("reload-interval", 5*time.Second, "How often to check the config file for changes (0 disables watching)")
+	readOnly := flag.Bool("read-only", false, "Disable every tool that changes UCloud resources, overriding policy.read_only")
+	dryRun := flag.Bool("dry-run", false, "Make every tool that changes UCloud resources return the requests it would send instead of sending them")
+	allowDegraded := flag.Bool("allow-degraded", false, "Start even if the configuration is incomplete or credentials fail verification")
 	flag.Parse()
 
 	// Print startup information
 	log.Printf("Using config file: %s", *configPath)
-	log.Printf("Server will listen on port: %s", *port)
 
 	// Load configuration
 	cfg, err := config.LoadConfig(*configPath)
-	if err != nil {
-		log.Printf("Failed to load config from file: %v, trying environment variables", err)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Printf("Config file %s not found, trying environment variables", *configPath)
 		// Try loading from environment variables
-		cfg = &config.Config{
-			Region:     os.Getenv("UCLOUD_REGION"),
-			ProjectID:  os.Getenv("UCLOUD_PROJECT_ID"),
-			PublicKey:  os.Getenv("UCLOUD_PUBLIC_KEY"),
-			PrivateKey: os.Getenv("UCLOUD_PRIVATE_KEY"),
+		cfg = config.LoadFromEnv()
+		if err := cfg.Validate(); err != nil {
+			failStartup(*allowDegraded, "Configuration from environment variables is incomplete: %v", err)
 		}
+	} else if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// The port in the config file applies unless --port is given
+	if cfg.Server.Port != 0 && !isFlagSet("port") {
+		*port = strconv.Itoa(cfg.Server.Port)
+	}
+	log.Printf("Server will listen on port: %s", *port)
+
+	// Command line profile overrides the active profile from the config file
+	if *profile != "" {
+		cfg.ActiveProfile = *profile
 	}
 
 	// Print configuration (Note: avoid printing sensitive information in production)
-	log.Printf("Using configuration - Region: %s, ProjectID: %s", cfg.Region, cfg.ProjectID)
+	for _, name := range cfg.ProfileNames() {
+		if p, err := cfg.GetProfile(name); err == nil {
+			log.Printf("Using configuration - Profile: %s, Region: %s, ProjectID: %s", name, p.Region, p.ProjectID)
+		}
+	}
+	log.Printf("Active profile: %s", cfg.DefaultProfile())
+
+	if *readOnly {
+		cfg.Policy.ReadOnly = true
+	}
+	if *dryRun {
+		cfg.Policy.DryRun = true
+	}
 
-	// Create UCloud client
-	ucloudClient, err := ucloud.NewUCloudClient(cfg)
+	// Create UCloud clients
+	config.SetSecretCacheTTL(cfg.SecretCacheTTLDuration())
+	clients, err := ucloud.NewClientSet(cfg)
 	if err != nil {
 		log.Fatalf("Failed to create UCloud client: %v", err)
 	}
 
+	// Verify credentials, region and project before serving any tools
+	log.Printf("Verifying UCloud credentials...")
+	if err := clients.Verify(); err != nil {
+		failStartup(*allowDegraded, "UCloud credential verification failed:\n%v", err)
+	} else {
+		log.Printf("UCloud credentials verified")
+	}
+
 	// Create MCP server
-	mcpServer := mcp.NewMCPServer(ucloudClient)
+	mcpServer, err := mcp.NewMCPServer(clients, cfg)
+	if err != nil {
+		log.Fatalf("Failed to create MCP server: %v", err)
+	}
+
+	// Reload configuration on SIGHUP and when the config file changes
+	go watchConfig(context.Background(), mcpServer, cfg, *configPath, *profile, *reloadInterval)
 
 	// Start server
 	if err := mcpServer.Start(*port); err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
 }
+
+// failStartup aborts startup, or only logs the problem when degraded mode is allowed
+func failStartup(allowDegraded bool, format string, args ...interface{}) {
+	if !allowDegraded {
+		log.Fatalf(format+"\nRefusing to start; pass --allow-degraded to start anyway", args...)
+	}
+	log.Printf(format, args...)
+	log.Printf("WARNING: starting in degraded mode, tool calls may fail")
+}
+
+// isFlagSet reports whether a flag was given on the command line
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// validateConfig implements the validate-config subcommand. It prints every
+// problem found in the config file and returns the process exit code.
+func validateConfig(args []string) int {
+	flags := flag.NewFlagSet("validate-config", flag.ExitOnError)
+	configPath := flags.String("config", "config.json", "Path to configuration file")
+	flags.Parse(args)
+
+	err := config.ValidateFile(*configPath)
+	if err == nil {
+		fmt.Printf("%s: configuration is valid\n", *configPath)
+		return 0
+	}
+
+	var validationErr *config.ValidationError
+	if !errors.As(err, &validationErr) {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *configPath, err)
+		return 1
+	}
+
+	for _, problem := range validationErr.Problems {
+		location := *configPath
+		if problem.Line > 0 {
+			location = fmt.Sprintf("%s:%d", *configPath, problem.Line)
+		}
+		if problem.Path != "" {
+			fmt.Fprintf(os.Stderr, "%s: %s: %s\n", location, problem.Path, problem.Message)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", location, problem.Message)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d problem(s) found\n", len(validationErr.Problems))
+	return 1
+}
+
+// approval implements the approval subcommand, which lists, approves and rejects
+// operations through the approval endpoint of a running server
+func approval(args []string) int {
+	flags := flag.NewFlagSet("approval", flag.ExitOnError)
+	endpoint := flags.String("url", "http://"+mcp.DefaultApprovalListen, "URL of the approval endpoint")
+	token := flags.String("token", os.Getenv("UCLOUD_MCP_APPROVAL_TOKEN"), "Approval token, if the server requires one")
+	approver := flags.String("approver", os.Getenv("USER"), "Name recorded as the approver when the server has no approval tokens; otherwise the token's identity is used")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s approval [flags] list | show <id> | approve <id> | reject <id>\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	var method, path string
+	switch {
+	case flags.NArg() == 1 && flags.Arg(0) == "list":
+		method, path = http.MethodGet, "/operations"
+	case flags.NArg() == 2 && flags.Arg(0) == "show":
+		method, path = http.MethodGet, "/operations/"+url.PathEscape(flags.Arg(1))
+	case flags.NArg() == 2 && (flags.Arg(0) == "approve" || flags.Arg(0) == "reject"):
+		if *approver == "" {
+			fmt.Fprintln(os.Stderr, "--approver is required")
+			return 1
+		}
+		method = http.MethodPost
+		path = "/operations/" + url.PathEscape(flags.Arg(1)) + "/" + flags.Arg(0) + "?approver=" + url.QueryEscape(*approver)
+	default:
+		flags.Usage()
+		return 2
+	}
+
+	req, err := http.NewRequest(method, strings.TrimSuffix(*endpoint, "/")+path, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if *token != "" {
+		req.Header.Set("Authorization", "Bearer "+*token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to reach approval endpoint: %v\n", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "%s", body)
+		return 1
+	}
+	fmt.Printf("%s", body)
+	return 0
+}
+
+// watchConfig reloads the configuration on SIGHUP and, if interval is non-zero,
+// whenever the config file or the instance ID allow-list changes on disk.
+// The allow-list is watched at the path set by the current configuration.
+// running is the configuration the server was started with.
+func watchConfig(ctx context.Context, mcpServer *mcp.MCPServer, running *config.Config, configPath, profile string, interval time.Duration) {
+	scopeFile := running.Scope.InstanceIDsFile
+
+	reloads := make(chan struct{}, 1)
+	requestReload := func() {
+		select {
+		case reloads <- struct{}{}:
+		default:
+			// A reload is already pending
+		}
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	stopScopeWatch := func() {}
+	watchScopeFile := func(path string) {
+		stopScopeWatch()
+		stopScopeWatch = func() {}
+		if interval <= 0 || path == "" {
+			return
+		}
+		scopeCtx, cancel := context.WithCancel(ctx)
+		stopScopeWatch = cancel
+		go config.WatchFile(scopeCtx, path, interval, requestReload)
+	}
+
+	if interval > 0 {
+		go config.WatchFile(ctx, configPath, interval, requestReload)
+	}
+	watchScopeFile(scopeFile)
+	defer func() { stopScopeWatch() }()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Printf("Received SIGHUP, reloading configuration")
+			requestReload()
+		case <-reloads:
+			cfg := reloadClients(mcpServer, running, configPath, profile)
+			if cfg != nil && cfg.Scope.InstanceIDsFile != scopeFile {
+				log.Printf("Instance ID allow-list moved from %q to %q", scopeFile, cfg.Scope.InstanceIDsFile)
+				scopeFile = cfg.Scope.InstanceIDsFile
+				watchScopeFile(scopeFile)
+			}
+		}
+	}
+}
+
+// reloadClients rebuilds the UCloud clients from the config file and swaps them
+// in only after the new credentials have been verified. Changes to sections that
+// need a restart are logged as warnings. It returns the applied configuration,
+// or nil if the current configuration was kept.
+func reloadClients(mcpServer *mcp.MCPServer, running *config.Config, configPath, profile string) *config.Config {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		log.Printf("Failed to reload config, keeping current configuration: %v", err)
+		return nil
+	}
+
+	if profile != "" {
+		cfg.ActiveProfile = profile
+	}
+
+	config.SetSecretCacheTTL(cfg.SecretCacheTTLDuration())
+	clients, err := ucloud.NewClientSet(cfg)
+	if err != nil {
+		log.Printf("Failed to create UCloud client, keeping current configuration: %v", err)
+		return nil
+	}
+
+	if err := clients.Verify(); err != nil {
+		log.Printf("New credentials failed verification, keeping current configuration: %v", err)
+		return nil
+	}
+
+	mcpServer.UpdateClients(clients)
+	log.Printf("Configuration reloaded, active profile: %s", cfg.DefaultProfile())
+	for _, section := range config.RestartRequired(running, cfg) {
+		log.Printf("Warning: the %s section changed but only takes effect after a restart", section)
+	}
+	return cfg
+}
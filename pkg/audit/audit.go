@@ -0,0 +1,164 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+)
+
+// Result statuses of audited tool calls
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// redacted replaces the values of arguments that may hold secrets
+const redacted = "[REDACTED]"
+
+// sensitiveArguments are substrings of argument names whose values are redacted
+var sensitiveArguments = []string{"password", "secret", "token", "private_key", "credential"}
+
+// Record is a single audit log entry describing one tool call
+type Record struct {
+	Time      time.Time `json:"time"`
+	Identity  string    `json:"identity"`
+	SessionID string    `json:"session_id,omitempty"`
+	Tool      string    `json:"tool"`
+
+	// OperationID is set when an operation runs after human approval
+	OperationID string `json:"operation_id,omitempty"`
+
+	Arguments  map[string]interface{} `json:"arguments,omitempty"`
+	APICalls   []ucloud.APICall       `json:"api_calls,omitempty"`
+	Status     string                 `json:"status"`
+	Error      string                 `json:"error,omitempty"`
+	DurationMS int64                  `json:"duration_ms"`
+}
+
+// Logger writes audit records as JSON lines. A nil Logger discards records.
+type Logger struct {
+	mu      sync.Mutex
+	writers []io.Writer
+	closers []io.Closer
+}
+
+// NewLogger creates the audit logger described by the configuration.
+// It returns nil when auditing is disabled.
+func NewLogger(cfg config.AuditConfig) (*Logger, error) {
+	if cfg.File == "" && !cfg.Syslog.Enabled {
+		return nil, nil
+	}
+
+	l := &Logger{}
+	if cfg.File != "" {
+		file, err := openRotatingFile(cfg.File, cfg.MaxSizeMB, cfg.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		l.writers = append(l.writers, file)
+		l.closers = append(l.closers, file)
+	}
+
+	if cfg.Syslog.Enabled {
+		writer, err := dialSyslog(cfg.Syslog)
+		if err != nil {
+			l.Close()
+			return nil, fmt.Errorf("failed to connect to syslog: %v", err)
+		}
+		l.writers = append(l.writers, writer)
+		l.closers = append(l.closers, writer)
+	}
+
+	return l, nil
+}
+
+// Log writes a record to every destination. Failures are reported in the debug log
+// because an audit failure must not fail the tool call.
+func (l *Logger) Log(record Record) {
+	if l == nil {
+		return
+	}
+
+	record.Arguments = Redact(record.Arguments)
+	data, err := json.Marshal(record)
+	if err != nil {
+		log.Printf("Failed to marshal audit record: %v", err)
+		return
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, writer := range l.writers {
+		if _, err := writer.Write(data); err != nil {
+			log.Printf("Failed to write audit record: %v", err)
+		}
+	}
+}
+
+// Close closes every destination
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var firstErr error
+	for _, closer := range l.closers {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Redact returns a copy of tool arguments with the values of sensitive arguments replaced
+func Redact(arguments map[string]interface{}) map[string]interface{} {
+	if arguments == nil {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(arguments))
+	for name, value := range arguments {
+		if isSensitive(name) {
+			result[name] = redacted
+			continue
+		}
+		result[name] = redactValue(value)
+	}
+	return result
+}
+
+// redactValue redacts sensitive arguments nested in maps and lists
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return Redact(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = redactValue(item)
+		}
+		return items
+	}
+	return value
+}
+
+// isSensitive reports whether an argument name suggests a secret value
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range sensitiveArguments {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		want      map[string]interface{}
+	}{
+		{
+			name:      "nil",
+			arguments: nil,
+			want:      nil,
+		},
+		{
+			name:      "plain arguments",
+			arguments: map[string]interface{}{"instance_id": "uhost-a", "limit": float64(10)},
+			want:      map[string]interface{}{"instance_id": "uhost-a", "limit": float64(10)},
+		},
+		{
+			name:      "sensitive names",
+			arguments: map[string]interface{}{"Password": "hunter2", "api_token": "t", "private_key": "k", "region": "cn-bj2"},
+			want:      map[string]interface{}{"Password": redacted, "api_token": redacted, "private_key": redacted, "region": "cn-bj2"},
+		},
+		{
+			name: "nested map",
+			arguments: map[string]interface{}{
+				"options": map[string]interface{}{"client_secret": "s", "name": "web"},
+			},
+			want: map[string]interface{}{
+				"options": map[string]interface{}{"client_secret": redacted, "name": "web"},
+			},
+		},
+		{
+			name: "maps in a list",
+			arguments: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{"port": "22", "credential": "c"},
+					"plain",
+					[]interface{}{map[string]interface{}{"token": "t"}},
+				},
+			},
+			want: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{"port": "22", "credential": redacted},
+					"plain",
+					[]interface{}{map[string]interface{}{"token": redacted}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.arguments); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Redact() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactDoesNotModifyArguments(t *testing.T) {
+	rules := []interface{}{map[string]interface{}{"password": "p"}}
+	arguments := map[string]interface{}{"rules": rules}
+
+	Redact(arguments)
+
+	if got := rules[0].(map[string]interface{})["password"]; got != "p" {
+		t.Errorf("Redact modified the original arguments: password = %v", got)
+	}
+}
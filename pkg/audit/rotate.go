@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Rotation defaults
+const (
+	defaultMaxSizeMB  = 100
+	defaultMaxBackups = 10
+)
+
+// rotatingFile is an append-only file that is rotated when it grows too large.
+// Rotated files are renamed to name.1, name.2, ... with name.1 the most recent.
+type rotatingFile struct {
+	mu         sync.Mutex
+	name       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// openRotatingFile opens or creates the file for appending
+func openRotatingFile(name string, maxSizeMB, maxBackups int) (*rotatingFile, error) {
+	if maxSizeMB == 0 {
+		maxSizeMB = defaultMaxSizeMB
+	}
+	if maxBackups == 0 {
+		maxBackups = defaultMaxBackups
+	}
+
+	f := &rotatingFile{
+		name:       name,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open opens the current file and records its size
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat audit log: %v", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// Write appends data, rotating first if the file would exceed its maximum size
+func (f *rotatingFile) Write(data []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.size > 0 && f.size+int64(len(data)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(data)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the backups and starts a new file
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %v", err)
+	}
+
+	os.Remove(fmt.Sprintf("%s.%d", f.name, f.maxBackups))
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", f.name, i), fmt.Sprintf("%s.%d", f.name, i+1))
+	}
+	if err := os.Rename(f.name, f.name+".1"); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %v", err)
+	}
+
+	return f.open()
+}
+
+// Close closes the current file
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package audit
+
+import (
+	"io"
+	"log/syslog"
+
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+)
+
+// defaultSyslogTag is the syslog tag used when none is configured
+const defaultSyslogTag = "ucloud-mcp-server"
+
+// dialSyslog connects to the local or remote syslog server
+func dialSyslog(cfg config.SyslogConfig) (io.WriteCloser, error) {
+	tag := cfg.Tag
+	if tag == "" {
+		tag = defaultSyslogTag
+	}
+	return syslog.Dial(cfg.Network, cfg.Address, syslog.LOG_INFO|syslog.LOG_AUTH, tag)
+}
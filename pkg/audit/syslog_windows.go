@@ -0,0 +1,13 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+)
+
+// dialSyslog reports that syslog is not available on Windows
+func dialSyslog(cfg config.SyslogConfig) (io.WriteCloser, error) {
+	return nil, fmt.Errorf("syslog is not supported on windows")
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// cliConfigEntry is one profile in the UCloud CLI's config.json
+type cliConfigEntry struct {
+	Profile   string `json:"profile"`
+	Active    bool   `json:"active"`
+	ProjectID string `json:"project_id"`
+	Region    string `json:"region"`
+	BaseURL   string `json:"base_url"`
+}
+
+// cliCredentialEntry is one profile in the UCloud CLI's credential.json
+type cliCredentialEntry struct {
+	Profile    string `json:"profile"`
+	PublicKey  string `json:"public_key"`
+	PrivateKey string `json:"private_key"`
+}
+
+// cliProfiles stores the profiles read from the UCloud CLI configuration
+type cliProfiles struct {
+	profiles map[string]Profile
+	active   string
+}
+
+// cliConfigDir returns the directory of the UCloud CLI configuration files
+func cliConfigDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ucloud"), nil
+}
+
+// loadCLIProfiles reads ~/.ucloud/config.json and ~/.ucloud/credential.json.
+// Missing files are not an error and result in no profiles.
+func loadCLIProfiles() (*cliProfiles, error) {
+	cli := &cliProfiles{profiles: make(map[string]Profile)}
+
+	dir, err := cliConfigDir()
+	if err != nil {
+		return cli, nil
+	}
+
+	var configs []cliConfigEntry
+	if err := readCLIFile(filepath.Join(dir, "config.json"), &configs); err != nil {
+		return nil, err
+	}
+
+	var credentials []cliCredentialEntry
+	if err := readCLIFile(filepath.Join(dir, "credential.json"), &credentials); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range configs {
+		profile := cli.profiles[entry.Profile]
+		profile.ProjectID = entry.ProjectID
+		profile.Region = entry.Region
+		profile.BaseURL = entry.BaseURL
+		cli.profiles[entry.Profile] = profile
+
+		if entry.Active {
+			cli.active = entry.Profile
+		}
+	}
+
+	for _, entry := range credentials {
+		profile := cli.profiles[entry.Profile]
+		profile.PublicKey = entry.PublicKey
+		profile.PrivateKey = entry.PrivateKey
+		cli.profiles[entry.Profile] = profile
+	}
+
+	return cli, nil
+}
+
+// readCLIFile parses a UCloud CLI JSON file, ignoring files that do not exist
+func readCLIFile(filename string, v interface{}) error {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read UCloud CLI file: %v", err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse UCloud CLI file %s: %v", filename, err)
+	}
+	return nil
+}
+
+// lookup returns the named CLI profile, or the CLI's active profile if name is empty
+func (c *cliProfiles) lookup(name string) (Profile, bool) {
+	if name == "" {
+		name = c.active
+	}
+	profile, ok := c.profiles[name]
+	return profile, ok
+}
+
+// applyCLI fills empty fields from a UCloud CLI profile
+func (p *Profile) applyCLI(cli Profile) {
+	if p.Region == "" {
+		p.Region = cli.Region
+	}
+	if p.ProjectID == "" {
+		p.ProjectID = cli.ProjectID
+	}
+	if p.PublicKey == "" {
+		p.PublicKey = cli.PublicKey
+	}
+	if p.PrivateKey == "" {
+		p.PrivateKey = cli.PrivateKey
+	}
+	if p.BaseURL == "" {
+		p.BaseURL = cli.BaseURL
+	}
+}
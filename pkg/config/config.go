@@ -1,76 +1,443 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
+	"sort"
+	"time"
 )
 
-// Config stores UCloud configuration information
-type Config struct {
-	Region     string `json:"region"`
-	ProjectID  string `json:"project_id"`
+// DefaultProfileName is the name of the profile formed by the top-level credential fields
+const DefaultProfileName = "default"
+
+// Profile stores the credentials and defaults of one UCloud account
+type Profile struct {
+	Region    string `json:"region"`
+	ProjectID string `json:"project_id"`
+
+	// Keys may be literals or secret references, see ResolveSecret
 	PublicKey  string `json:"public_key"`
 	PrivateKey string `json:"private_key"`
+
+	// SecurityToken is the optional token of temporary (STS) keys, also a literal or secret reference
+	SecurityToken string `json:"security_token,omitempty"`
+
+	BaseURL string `json:"base_url,omitempty"`
+
+	// Projects is an optional allow-list of project IDs that tools may target.
+	// When empty, any project visible to the credentials can be selected.
+	Projects []string `json:"projects,omitempty"`
+}
+
+// Config stores UCloud configuration information
+type Config struct {
+	// Top-level credential fields form the "default" profile
+	Profile
+
+	// Profiles holds additional named profiles, e.g. "staging" and "production"
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+
+	// ActiveProfile is the profile used when a tool call does not select one
+	ActiveProfile string `json:"active_profile,omitempty"`
+
+	// RequireExplicitProfile makes tools reject calls that do not pass a profile
+	// argument when more than one profile is configured
+	RequireExplicitProfile bool `json:"require_explicit_profile,omitempty"`
+
+	// CLIProfile selects the UCloud CLI profile used to fill the default profile.
+	// When empty, the CLI's active profile is used.
+	CLIProfile string `json:"cli_profile,omitempty"`
+
+	// SecretCacheTTL is how long exec: credential helper output is cached, e.g. "15m"
+	SecretCacheTTL string `json:"secret_cache_ttl,omitempty"`
+
+	Server    ServerConfig    `json:"server,omitempty"`
+	Transport TransportConfig `json:"transport,omitempty"`
+	Auth      AuthConfig      `json:"auth,omitempty"`
+	Regions   RegionsConfig   `json:"regions,omitempty"`
+	HTTP      HTTPConfig      `json:"http,omitempty"`
+	Policy    PolicyConfig    `json:"policy,omitempty"`
+	Scope     ScopeConfig     `json:"scope,omitempty"`
+	Audit     AuditConfig     `json:"audit,omitempty"`
+	Approval  ApprovalConfig  `json:"approval,omitempty"`
+	Quotas    QuotaConfig     `json:"quotas,omitempty"`
+
+	// Roles grant authenticated callers access to tools, regions, projects and tags.
+	// When empty, every caller may use every tool.
+	Roles map[string]RoleConfig `json:"roles,omitempty"`
+}
+
+// HTTPConfig configures the HTTP client used for UCloud API requests
+type HTTPConfig struct {
+	// Timeout is the timeout of every API request, e.g. "30s"
+	Timeout string `json:"timeout,omitempty"`
+
+	// MaxRetries is the number of retries for network errors and retryable API errors
+	MaxRetries int `json:"max_retries,omitempty"`
+
+	// Proxy is the URL of an HTTP(S) proxy. When empty, HTTPS_PROXY and related
+	// environment variables are honoured.
+	Proxy string `json:"proxy,omitempty"`
+
+	// CABundle is the path of a PEM file with additional trusted CA certificates
+	CABundle string `json:"ca_bundle,omitempty"`
+
+	// UserAgent is appended to the server's user agent, e.g. "team-sre"
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// ServerConfig configures the MCP server itself
+type ServerConfig struct {
+	// Name is the server name reported to MCP clients
+	Name string `json:"name,omitempty"`
+
+	// Port is the port the SSE transport listens on; the --port flag takes precedence
+	Port int `json:"port,omitempty"`
+}
+
+// TransportConfig configures the SSE transport MCP clients connect to
+type TransportConfig struct {
+	// BaseURL is the externally reachable URL advertised to SSE clients,
+	// e.g. "https://mcp.example.com". Defaults to http://localhost:<port>.
+	BaseURL string `json:"base_url,omitempty"`
+}
+
+// AuthConfig configures authentication of MCP clients on the SSE transport
+type AuthConfig struct {
+	// Tokens lists the bearer tokens accepted by the server.
+	// When empty, clients are not authenticated.
+	Tokens []AuthToken `json:"tokens,omitempty"`
+}
+
+// AuthToken maps a bearer token to the identity of its caller
+type AuthToken struct {
+	Identity string `json:"identity"`
+
+	// Token may be a literal or a secret reference, see ResolveSecret
+	Token string `json:"token"`
+}
+
+// PolicyConfig restricts which tools, resources and prompts the server advertises
+type PolicyConfig struct {
+	// ReadOnly disables every tool that changes UCloud resources
+	ReadOnly bool `json:"read_only,omitempty"`
+
+	// DryRun makes every tool that changes UCloud resources report the requests
+	// it would send instead of sending them
+	DryRun bool `json:"dry_run,omitempty"`
+
+	Tools     FilterConfig `json:"tools,omitempty"`
+	Resources FilterConfig `json:"resources,omitempty"`
+	Prompts   FilterConfig `json:"prompts,omitempty"`
+}
+
+// FilterConfig enables or disables items by name or glob pattern such as "instance_*".
+// When Enabled is empty every item is enabled; Disabled takes precedence over Enabled.
+type FilterConfig struct {
+	Enabled  []string `json:"enabled,omitempty"`
+	Disabled []string `json:"disabled,omitempty"`
+}
+
+// ScopeConfig restricts the instances the server can see or act on.
+// An instance is in scope if its tag matches one of Tags or its ID is allow-listed.
+// When nothing is set, every instance is in scope.
+type ScopeConfig struct {
+	// Tags lists business group tags or glob patterns such as "ai-*"
+	Tags []string `json:"tags,omitempty"`
+
+	// InstanceIDs allow-lists instances by ID
+	InstanceIDs []string `json:"instance_ids,omitempty"`
+
+	// InstanceIDsFile is a file with one allow-listed instance ID per line
+	InstanceIDsFile string `json:"instance_ids_file,omitempty"`
+}
+
+// AuditConfig configures the audit log of tool calls.
+// Auditing is disabled unless a file or syslog is configured.
+type AuditConfig struct {
+	// File is the path of the JSON lines audit log
+	File string `json:"file,omitempty"`
+
+	// MaxSizeMB is the size at which the file is rotated, 100 by default
+	MaxSizeMB int `json:"max_size_mb,omitempty"`
+
+	// MaxBackups is the number of rotated files to keep, 10 by default
+	MaxBackups int `json:"max_backups,omitempty"`
+
+	Syslog SyslogConfig `json:"syslog,omitempty"`
+}
+
+// SyslogConfig sends audit records to syslog
+type SyslogConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Network and Address select a remote syslog server, e.g. "udp" and "syslog.example.com:514".
+	// When empty, the local syslog daemon is used.
+	Network string `json:"network,omitempty"`
+	Address string `json:"address,omitempty"`
+
+	// Tag is the syslog tag, "ucloud-mcp-server" by default
+	Tag string `json:"tag,omitempty"`
+}
+
+// ApprovalConfig parks mutating tool calls until a human approves them
+type ApprovalConfig struct {
+	// Rules select the tool calls that need approval. When empty, no approval is needed.
+	Rules []ApprovalRule `json:"rules,omitempty"`
+
+	// Listen is the address of the approval endpoint, "127.0.0.1:8090" by default
+	Listen string `json:"listen,omitempty"`
+
+	// Tokens map approver bearer tokens to approver identities. They are required
+	// when Listen is not a loopback address. Without tokens, approvers name themselves.
+	Tokens []AuthToken `json:"tokens,omitempty"`
+
+	// Retention is how long decided and expired operations are kept, "24h" by default
+	Retention string `json:"retention,omitempty"`
+
+	// Timeout is how long an operation waits for approval before it expires, "1h" by default
+	Timeout string `json:"timeout,omitempty"`
 }
 
-// LoadConfig loads configuration from file
+// ApprovalRule requires approval for mutating tools matching Tools that affect
+// resources matching Tags. Empty lists match everything.
+type ApprovalRule struct {
+	Tools []string `json:"tools,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// QuotaConfig limits tool usage per MCP session and per authenticated caller
+type QuotaConfig struct {
+	Session QuotaLimits `json:"session,omitempty"`
+	Caller  QuotaLimits `json:"caller,omitempty"`
+
+	// MaxResourcesPerCall limits how many resources a single mutating call may affect
+	MaxResourcesPerCall int `json:"max_resources_per_call,omitempty"`
+}
+
+// QuotaLimits are the limits applied to one session or caller. Zero means unlimited.
+type QuotaLimits struct {
+	// CallsPerMinute limits the calls of each tool
+	CallsPerMinute int `json:"calls_per_minute,omitempty"`
+
+	// Tools overrides CallsPerMinute for tools matching a name or glob pattern
+	Tools map[string]int `json:"tools,omitempty"`
+
+	// MutationsPerHour limits the calls that change UCloud resources
+	MutationsPerHour int `json:"mutations_per_hour,omitempty"`
+}
+
+// RoleConfig grants a set of callers access to tools and UCloud resources.
+// Every list holds names or glob patterns; an empty list other than Identities allows everything.
+type RoleConfig struct {
+	// Identities are the auth token identities granted the role, "*" for every caller
+	Identities []string `json:"identities"`
+
+	Tools    []string `json:"tools,omitempty"`
+	Regions  []string `json:"regions,omitempty"`
+	Projects []string `json:"projects,omitempty"`
+
+	// Tags selects the resources the role may see by their business group tag
+	Tags []string `json:"tags,omitempty"`
+}
+
+// RegionsConfig restricts the regions profiles may use
+type RegionsConfig struct {
+	// Allowed lists the regions profiles may be configured with. When empty, any region is allowed.
+	Allowed []string `json:"allowed,omitempty"`
+}
+
+// ServerName returns the configured server name, defaulting to "UCloud Instance Manager"
+func (c *Config) ServerName() string {
+	if c.Server.Name == "" {
+		return "UCloud Instance Manager"
+	}
+	return c.Server.Name
+}
+
+// LoadConfig loads configuration from a JSON, YAML or TOML file. Fields missing
+// from the file are filled from the UCloud CLI configuration and environment variables,
+// and files named by the configuration must exist.
+// Invalid files return a *ValidationError listing every problem found.
 func LoadConfig(filename string) (*Config, error) {
+	return loadConfig(filename, true)
+}
+
+// ValidateFile checks a config file on its own, without filling missing fields from
+// the UCloud CLI configuration or environment variables or checking that the files
+// it names exist, so the result does not depend on the host.
+// Invalid files return a *ValidationError.
+func ValidateFile(filename string) error {
+	_, err := loadConfig(filename, false)
+	return err
+}
+
+// loadConfig parses and validates a config file. When onHost is set, fallbacks
+// are applied first and the files named by the configuration are checked.
+func loadConfig(filename string, onHost bool) (*Config, error) {
 	// Read file content
 	data, err := os.ReadFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %v", err)
+		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	// Parse JSON
-	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %v", err)
+	// Parse the file, rejecting unknown fields and values of the wrong type
+	config, lines, problems := decodeConfig(filename, data)
+	if config == nil {
+		return nil, &ValidationError{File: filename, Problems: problems}
 	}
 
-	// Validate required fields
-	if config.Region == "" {
-		config.Region = os.Getenv("UCLOUD_REGION") // Try reading from environment variables
+	if onHost {
+		if err := config.applyFallbacks(); err != nil {
+			return nil, err
+		}
 	}
-	if config.ProjectID == "" {
-		config.ProjectID = os.Getenv("UCLOUD_PROJECT_ID")
+
+	problems = mergeProblems(problems, config.problems())
+	if onHost {
+		problems = mergeProblems(problems, config.fileProblems())
 	}
-	if config.PublicKey == "" {
-		config.PublicKey = os.Getenv("UCLOUD_PUBLIC_KEY")
+	if len(problems) > 0 {
+		lines.locate(problems)
+		sort.SliceStable(problems, func(i, j int) bool {
+			return problems[i].Line < problems[j].Line
+		})
+		return nil, &ValidationError{File: filename, Problems: problems}
 	}
-	if config.PrivateKey == "" {
-		config.PrivateKey = os.Getenv("UCLOUD_PRIVATE_KEY")
+
+	return config, nil
+}
+
+// SecretCacheTTLDuration returns how long credential helper output without an expiration is cached
+func (c *Config) SecretCacheTTLDuration() time.Duration {
+	if ttl, err := time.ParseDuration(c.SecretCacheTTL); err == nil && ttl > 0 {
+		return ttl
 	}
+	return DefaultSecretCacheTTL
+}
 
-	// Check if required fields exist
-	var missingFields []string
-	if config.Region == "" {
-		missingFields = append(missingFields, "region")
+// LoadFromEnv loads configuration from the UCloud CLI files and environment variables
+func LoadFromEnv() *Config {
+	config := &Config{}
+	if err := config.applyFallbacks(); err != nil {
+		// Environment variables are still usable without the CLI files
+		config.Profile.applyEnv()
 	}
-	if config.ProjectID == "" {
-		missingFields = append(missingFields, "project_id")
+	return config
+}
+
+// applyFallbacks fills fields missing from the config file, first from the
+// UCloud CLI configuration and then from environment variables
+func (c *Config) applyFallbacks() error {
+	cli, err := loadCLIProfiles()
+	if err != nil {
+		return err
 	}
-	if config.PublicKey == "" {
-		missingFields = append(missingFields, "public_key")
+
+	// Fill named profiles from the CLI profile of the same name
+	for name, profile := range c.Profiles {
+		if cliProfile, ok := cli.lookup(name); ok {
+			profile.applyCLI(cliProfile)
+			c.Profiles[name] = profile
+		}
 	}
-	if config.PrivateKey == "" {
-		missingFields = append(missingFields, "private_key")
+
+	// Fill the default profile unless only named profiles are configured
+	if len(c.Profiles) == 0 || c.PublicKey != "" {
+		if cliProfile, ok := cli.lookup(c.CLIProfile); ok {
+			c.Profile.applyCLI(cliProfile)
+		}
+		c.Profile.applyEnv()
 	}
 
-	if len(missingFields) > 0 {
-		return nil, fmt.Errorf("missing required fields: %s", strings.Join(missingFields, ", "))
+	return nil
+}
+
+// applyEnv fills empty fields from environment variables
+func (p *Profile) applyEnv() {
+	if p.Region == "" {
+		p.Region = os.Getenv("UCLOUD_REGION")
+	}
+	if p.ProjectID == "" {
+		p.ProjectID = os.Getenv("UCLOUD_PROJECT_ID")
+	}
+	if p.PublicKey == "" {
+		p.PublicKey = os.Getenv("UCLOUD_PUBLIC_KEY")
+	}
+	if p.PrivateKey == "" {
+		p.PrivateKey = os.Getenv("UCLOUD_PRIVATE_KEY")
 	}
+	if p.BaseURL == "" {
+		p.BaseURL = os.Getenv("UCLOUD_BASE_URL")
+	}
+}
 
-	return &config, nil
+// IsProjectAllowed reports whether the project may be targeted by tools
+func (p *Profile) IsProjectAllowed(projectID string) bool {
+	if len(p.Projects) == 0 {
+		return true
+	}
+	for _, project := range p.Projects {
+		if project == projectID {
+			return true
+		}
+	}
+	return false
 }
 
-// LoadFromEnv loads configuration from environment variables
-func LoadFromEnv() *Config {
-	return &Config{
-		Region:     os.Getenv("UCLOUD_REGION"),
-		ProjectID:  os.Getenv("UCLOUD_PROJECT_ID"),
-		PublicKey:  os.Getenv("UCLOUD_PUBLIC_KEY"),
-		PrivateKey: os.Getenv("UCLOUD_PRIVATE_KEY"),
+// hasDefaultProfile reports whether the top-level credential fields are in use
+func (c *Config) hasDefaultProfile() bool {
+	if _, ok := c.Profiles[DefaultProfileName]; ok {
+		return true
 	}
+	return c.PublicKey != "" || len(c.Profiles) == 0
+}
+
+// ProfileNames returns the names of all configured profiles in sorted order
+func (c *Config) ProfileNames() []string {
+	var names []string
+	if c.hasDefaultProfile() {
+		names = append(names, DefaultProfileName)
+	}
+	for name := range c.Profiles {
+		if name != DefaultProfileName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultProfile returns the name of the profile used when none is selected
+func (c *Config) DefaultProfile() string {
+	if c.ActiveProfile != "" {
+		return c.ActiveProfile
+	}
+	if c.hasDefaultProfile() {
+		return DefaultProfileName
+	}
+	if names := c.ProfileNames(); len(names) == 1 {
+		return names[0]
+	}
+	return ""
+}
+
+// GetProfile returns the named profile, or the active profile if name is empty
+func (c *Config) GetProfile(name string) (*Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile()
+		if name == "" {
+			return nil, fmt.Errorf("multiple profiles configured, set active_profile or pass --profile")
+		}
+	}
+
+	if profile, ok := c.Profiles[name]; ok {
+		return &profile, nil
+	}
+	if name == DefaultProfileName && c.hasDefaultProfile() {
+		profile := c.Profile
+		return &profile, nil
+	}
+
+	return nil, fmt.Errorf("profile %s not found", name)
 }
@@ -0,0 +1,354 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config file formats
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+	formatTOML = "toml"
+)
+
+// formatOf returns the config file format implied by the file extension
+func formatOf(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return formatYAML
+	case ".toml":
+		return formatTOML
+	default:
+		return formatJSON
+	}
+}
+
+// lineIndex maps dotted field paths to the line they are defined on
+type lineIndex map[string]int
+
+// lookup returns the line of a path, falling back to its closest defined parent
+func (l lineIndex) lookup(path string) int {
+	for path != "" {
+		if line, ok := l[path]; ok {
+			return line
+		}
+		cut := strings.LastIndexAny(path, ".[")
+		if cut < 0 {
+			break
+		}
+		path = path[:cut]
+	}
+	return 0
+}
+
+// locate fills in the line of every problem that does not have one yet
+func (l lineIndex) locate(problems []Problem) {
+	for i := range problems {
+		if problems[i].Line == 0 {
+			problems[i].Line = l.lookup(problems[i].Path)
+		}
+	}
+}
+
+// decodeConfig parses a config file strictly. It returns a nil config only when
+// the file cannot be parsed at all; unknown fields and type mismatches are reported
+// as problems alongside the best-effort decoded config.
+func decodeConfig(filename string, data []byte) (*Config, lineIndex, []Problem) {
+	var raw interface{}
+	lines := make(lineIndex)
+
+	switch formatOf(filename) {
+	case formatTOML:
+		var table map[string]interface{}
+		if _, err := toml.Decode(string(data), &table); err != nil {
+			return nil, lines, []Problem{tomlProblem(err)}
+		}
+		raw = table
+		indexTOML(data, lines)
+
+	default:
+		// JSON is parsed with the YAML parser as well to get line numbers
+		if formatOf(filename) == formatJSON {
+			if err := json.Unmarshal(data, &raw); err != nil {
+				return nil, lines, []Problem{jsonProblem(data, err)}
+			}
+		}
+
+		var root yaml.Node
+		if err := yaml.Unmarshal(data, &root); err != nil {
+			return nil, lines, []Problem{{Message: err.Error()}}
+		}
+		if err := root.Decode(&raw); err != nil {
+			return nil, lines, []Problem{{Message: err.Error()}}
+		}
+		indexYAML(&root, "", lines)
+	}
+
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+
+	// Round-trip through JSON so every format yields the same value types
+	// and the struct tags remain the single source of truth
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, lines, []Problem{{Message: err.Error()}}
+	}
+	if err := json.Unmarshal(encoded, &raw); err != nil {
+		return nil, lines, []Problem{{Message: err.Error()}}
+	}
+
+	problems := checkFields(reflect.TypeOf(Config{}), raw, "")
+	lines.locate(problems)
+
+	// Type mismatches were reported above and are skipped by the decoder
+	var config Config
+	var typeErr *json.UnmarshalTypeError
+	if err := json.Unmarshal(encoded, &config); err != nil && !errors.As(err, &typeErr) {
+		return nil, lines, append(problems, Problem{Message: err.Error()})
+	}
+
+	return &config, lines, problems
+}
+
+// jsonProblem converts a JSON syntax error to a problem with a line number
+func jsonProblem(data []byte, err error) Problem {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line := bytes.Count(data[:syntaxErr.Offset], []byte("\n")) + 1
+		return Problem{Line: line, Message: syntaxErr.Error()}
+	}
+	return Problem{Message: err.Error()}
+}
+
+// tomlProblem converts a TOML parse error to a problem with a line number
+func tomlProblem(err error) Problem {
+	var parseErr toml.ParseError
+	if errors.As(err, &parseErr) {
+		return Problem{Line: parseErr.Position.Line, Message: parseErr.Message}
+	}
+	return Problem{Message: err.Error()}
+}
+
+// indexYAML records the line of every mapping key and sequence item
+func indexYAML(node *yaml.Node, path string, lines lineIndex) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			indexYAML(child, path, lines)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := joinPath(path, key.Value)
+			lines[childPath] = key.Line
+			indexYAML(value, childPath, lines)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			lines[childPath] = item.Line
+			indexYAML(item, childPath, lines)
+		}
+	}
+}
+
+var (
+	tomlTablePattern = regexp.MustCompile(`^\s*\[\[?\s*([^\]]+?)\s*\]\]?`)
+	tomlKeyPattern   = regexp.MustCompile(`^\s*("[^"]*"|[A-Za-z0-9_-]+)\s*=`)
+)
+
+// indexTOML records the line of table headers and keys. Arrays of tables are
+// indexed by position; inline tables are attributed to the line of their key.
+func indexTOML(data []byte, lines lineIndex) {
+	arrayCounts := make(map[string]int)
+	table := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		text := scanner.Text()
+
+		if m := tomlTablePattern.FindStringSubmatch(text); m != nil {
+			table = strings.ReplaceAll(m[1], " ", "")
+			table = strings.ReplaceAll(table, `"`, "")
+			if strings.HasPrefix(strings.TrimSpace(text), "[[") {
+				index := arrayCounts[table]
+				arrayCounts[table]++
+				table = fmt.Sprintf("%s[%d]", table, index)
+			}
+			lines[table] = lineNo
+			continue
+		}
+
+		if m := tomlKeyPattern.FindStringSubmatch(text); m != nil {
+			lines[joinPath(table, strings.Trim(m[1], `"`))] = lineNo
+		}
+	}
+}
+
+// joinPath appends a key to a dotted path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// checkFields compares a decoded value against the Go type it will be decoded
+// into and reports unknown fields and values of the wrong kind
+func checkFields(t reflect.Type, value interface{}, path string) []Problem {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// null is treated as an absent value
+	if value == nil {
+		return nil
+	}
+
+	mismatch := func(expected string) []Problem {
+		return []Problem{{Path: path, Message: fmt.Sprintf("expected %s, got %s", expected, describeValue(value))}}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return mismatch("an object")
+		}
+
+		fields := make(map[string]reflect.Type)
+		collectFields(t, fields)
+
+		var problems []Problem
+		for _, key := range sortedKeys(object) {
+			childPath := joinPath(path, key)
+			fieldType, ok := fields[key]
+			if !ok {
+				problems = append(problems, Problem{Path: childPath, Message: "unknown field"})
+				continue
+			}
+			problems = append(problems, checkFields(fieldType, object[key], childPath)...)
+		}
+		return problems
+
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return mismatch("an object")
+		}
+
+		var problems []Problem
+		for _, key := range sortedKeys(object) {
+			problems = append(problems, checkFields(t.Elem(), object[key], joinPath(path, key))...)
+		}
+		return problems
+
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			return mismatch("a list")
+		}
+
+		var problems []Problem
+		for i, item := range items {
+			problems = append(problems, checkFields(t.Elem(), item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return problems
+
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			return mismatch("a string")
+		}
+
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			return mismatch("a boolean")
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !isInteger(value) {
+			return mismatch("an integer")
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if _, ok := value.(float64); !ok {
+			return mismatch("a number")
+		}
+	}
+
+	return nil
+}
+
+// collectFields maps JSON field names to their types, flattening embedded structs
+func collectFields(t reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			collectFields(field.Type, fields)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+}
+
+// isInteger reports whether a decoded value is a whole number
+func isInteger(value interface{}) bool {
+	v, ok := value.(float64)
+	return ok && v == math.Trunc(v)
+}
+
+// describeValue names the kind of a decoded value for error messages
+func describeValue(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "a list"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Secret reference prefixes. Values without a prefix are used as-is.
+const (
+	secretFilePrefix = "file:"
+	secretEnvPrefix  = "env:"
+	secretExecPrefix = "exec:"
+)
+
+// DefaultSecretCacheTTL is how long exec helper output is cached when it carries no expiration
+const DefaultSecretCacheTTL = 15 * time.Minute
+
+// secretExecTimeout bounds how long a credential helper may run
+const secretExecTimeout = 30 * time.Second
+
+// execSecretOutput is the optional JSON output format of a credential helper
+type execSecretOutput struct {
+	Value      string    `json:"value"`
+	Expiration time.Time `json:"expiration"`
+}
+
+// cachedSecret is a resolved secret and the time it stops being valid
+type cachedSecret struct {
+	value   string
+	expires time.Time
+}
+
+// secretResolver resolves secret references and caches credential helper output
+type secretResolver struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	cache map[string]cachedSecret
+}
+
+var secrets = &secretResolver{
+	ttl:   DefaultSecretCacheTTL,
+	cache: make(map[string]cachedSecret),
+}
+
+// SetSecretCacheTTL sets how long credential helper output without an expiration is cached
+func SetSecretCacheTTL(ttl time.Duration) {
+	secrets.mu.Lock()
+	defer secrets.mu.Unlock()
+	secrets.ttl = ttl
+}
+
+// IsSecretReference reports whether the value refers to an external secret source
+func IsSecretReference(value string) bool {
+	return strings.HasPrefix(value, secretFilePrefix) ||
+		strings.HasPrefix(value, secretEnvPrefix) ||
+		strings.HasPrefix(value, secretExecPrefix)
+}
+
+// checkSecretReference checks the syntax of a secret reference without resolving it.
+// Literal values are always valid.
+func checkSecretReference(value string) error {
+	switch {
+	case strings.HasPrefix(value, secretFilePrefix):
+		if strings.TrimPrefix(value, secretFilePrefix) == "" {
+			return fmt.Errorf("file: reference needs a path")
+		}
+	case strings.HasPrefix(value, secretEnvPrefix):
+		if strings.TrimPrefix(value, secretEnvPrefix) == "" {
+			return fmt.Errorf("env: reference needs a variable name")
+		}
+	case strings.HasPrefix(value, secretExecPrefix):
+		if _, err := execArgs(strings.TrimPrefix(value, secretExecPrefix)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResolveSecret resolves a secret value, which may be a literal or one of:
+//
+//	file:/run/secrets/ucloud_private_key          read from a file
+//	env:UCLOUD_PRIVATE_KEY                        read from an environment variable
+//	exec:["/usr/local/bin/vault-helper","ucloud"] run a credential helper, given as a JSON argv list
+//
+// The returned expiration is zero when the secret does not expire.
+func ResolveSecret(value string) (string, time.Time, error) {
+	switch {
+	case strings.HasPrefix(value, secretFilePrefix):
+		path := strings.TrimPrefix(value, secretFilePrefix)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to read secret file: %v", err)
+		}
+		return strings.TrimSpace(string(data)), time.Time{}, nil
+
+	case strings.HasPrefix(value, secretEnvPrefix):
+		name := strings.TrimPrefix(value, secretEnvPrefix)
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", time.Time{}, fmt.Errorf("secret environment variable %s is not set", name)
+		}
+		return secret, time.Time{}, nil
+
+	case strings.HasPrefix(value, secretExecPrefix):
+		return secrets.resolveExec(strings.TrimPrefix(value, secretExecPrefix))
+	}
+
+	return value, time.Time{}, nil
+}
+
+// resolveExec runs a credential helper, reusing its cached output until it expires.
+// The helper runs without holding the lock, so a slow helper does not block others.
+func (r *secretResolver) resolveExec(command string) (string, time.Time, error) {
+	r.mu.Lock()
+	cached, ok := r.cache[command]
+	ttl := r.ttl
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.value, cached.expires, nil
+	}
+
+	args, err := execArgs(command)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretExecTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", time.Time{}, fmt.Errorf("credential helper %s failed: %v: %s", args[0], err, msg)
+		}
+		return "", time.Time{}, fmt.Errorf("credential helper %s failed: %v", args[0], err)
+	}
+
+	secret := cachedSecret{
+		value:   strings.TrimSpace(stdout.String()),
+		expires: time.Now().Add(ttl),
+	}
+
+	// Helpers may return JSON with an explicit expiration instead of plain text
+	var output execSecretOutput
+	if json.Unmarshal(stdout.Bytes(), &output) == nil && output.Value != "" {
+		secret.value = output.Value
+		if !output.Expiration.IsZero() {
+			secret.expires = output.Expiration
+		}
+	}
+
+	if secret.value == "" {
+		return "", time.Time{}, fmt.Errorf("credential helper %s returned an empty secret", args[0])
+	}
+
+	r.mu.Lock()
+	r.cache[command] = secret
+	r.mu.Unlock()
+	return secret.value, secret.expires, nil
+}
+
+// execArgs parses the argv list of an exec: reference, e.g. ["/usr/local/bin/helper","read","key"].
+// A list keeps arguments and paths containing spaces intact.
+func execArgs(command string) ([]string, error) {
+	var args []string
+	if err := json.Unmarshal([]byte(command), &args); err != nil {
+		return nil, fmt.Errorf("credential helper command must be a JSON list such as [\"/path/to/helper\", \"arg\"]: %v", err)
+	}
+	if len(args) == 0 || args[0] == "" {
+		return nil, fmt.Errorf("empty credential helper command")
+	}
+	return args, nil
+}
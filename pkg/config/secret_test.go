@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestExecArgs(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{`["/usr/local/bin/helper"]`, []string{"/usr/local/bin/helper"}, false},
+		{`["/opt/My Tools/helper", "read", "ucloud/private key"]`, []string{"/opt/My Tools/helper", "read", "ucloud/private key"}, false},
+		{`/usr/local/bin/helper read`, nil, true},
+		{`[]`, nil, true},
+		{`[""]`, nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := execArgs(tt.command)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("execArgs(%q) error = %v, wantErr %v", tt.command, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("execArgs(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestResolveSecretExecPathWithSpaces(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script helper")
+	}
+
+	dir := filepath.Join(t.TempDir(), "my helpers")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	helper := filepath.Join(dir, "helper")
+	if err := os.WriteFile(helper, []byte("#!/bin/sh\necho \"$1\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, err := ResolveSecret(`exec:["` + helper + `", "secret with spaces"]`)
+	if err != nil {
+		t.Fatalf("ResolveSecret: %v", err)
+	}
+	if got != "secret with spaces" {
+		t.Errorf("ResolveSecret = %q, want %q", got, "secret with spaces")
+	}
+}
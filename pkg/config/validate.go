@@ -0,0 +1,385 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Problem is a single error found in a configuration
+type Problem struct {
+	// Path is the dotted path of the offending field, e.g. "profiles.prod.region"
+	Path string
+
+	// Line is the line of the field in the config file, or 0 if unknown
+	Line int
+
+	Message string
+}
+
+// String formats the problem as "line 12: profiles.prod.region: message"
+func (p Problem) String() string {
+	var parts []string
+	if p.Line > 0 {
+		parts = append(parts, fmt.Sprintf("line %d", p.Line))
+	}
+	if p.Path != "" {
+		parts = append(parts, p.Path)
+	}
+	parts = append(parts, p.Message)
+	return strings.Join(parts, ": ")
+}
+
+// ValidationError reports every problem found in a configuration
+type ValidationError struct {
+	File     string
+	Problems []Problem
+}
+
+// Error lists all problems, one per line
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	if e.File != "" {
+		fmt.Fprintf(&b, "invalid config file %s:", e.File)
+	} else {
+		b.WriteString("invalid configuration:")
+	}
+	for _, problem := range e.Problems {
+		b.WriteString("\n  ")
+		b.WriteString(problem.String())
+	}
+	return b.String()
+}
+
+// Validate checks that the configuration is complete and consistent
+func (c *Config) Validate() error {
+	if problems := mergeProblems(c.problems(), c.fileProblems()); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// fileProblems checks that the files the configuration refers to exist. They are
+// not part of problems because the result depends on the host.
+func (c *Config) fileProblems() []Problem {
+	files := []struct{ path, filename string }{
+		{"http.ca_bundle", c.HTTP.CABundle},
+		{"scope.instance_ids_file", c.Scope.InstanceIDsFile},
+	}
+
+	var problems []Problem
+	for _, file := range files {
+		if file.filename == "" {
+			continue
+		}
+		if _, err := os.Stat(file.filename); err != nil {
+			problems = append(problems, Problem{Path: file.path, Message: err.Error()})
+		}
+	}
+	return problems
+}
+
+// problems returns every semantic problem of the configuration
+func (c *Config) problems() []Problem {
+	var problems []Problem
+	add := func(path, format string, args ...interface{}) {
+		problems = append(problems, Problem{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Profiles
+	names := c.ProfileNames()
+	if len(names) == 0 {
+		add("", "no profile configured")
+	}
+	for _, name := range names {
+		profile, _ := c.GetProfile(name)
+		prefix := ""
+		if _, ok := c.Profiles[name]; ok {
+			prefix = "profiles." + name + "."
+		}
+		for _, problem := range profile.problems(c) {
+			problem.Path = prefix + problem.Path
+			problems = append(problems, problem)
+		}
+	}
+	if len(names) > 0 {
+		if _, err := c.GetProfile(""); err != nil {
+			add("active_profile", "%v", err)
+		}
+	}
+
+	if c.SecretCacheTTL != "" {
+		if ttl, err := time.ParseDuration(c.SecretCacheTTL); err != nil || ttl <= 0 {
+			add("secret_cache_ttl", "must be a positive duration such as \"15m\"")
+		}
+	}
+
+	// Server
+	if c.Server.Port < 0 || c.Server.Port > 65535 {
+		add("server.port", "must be between 1 and 65535")
+	}
+
+	// Transport
+	if c.Transport.BaseURL != "" && !strings.HasPrefix(c.Transport.BaseURL, "http://") &&
+		!strings.HasPrefix(c.Transport.BaseURL, "https://") {
+		add("transport.base_url", "must be an http:// or https:// URL")
+	}
+
+	// HTTP client
+	if c.HTTP.Timeout != "" {
+		if timeout, err := time.ParseDuration(c.HTTP.Timeout); err != nil || timeout <= 0 {
+			add("http.timeout", "must be a positive duration such as \"30s\"")
+		}
+	}
+	if c.HTTP.MaxRetries < 0 {
+		add("http.max_retries", "must not be negative")
+	}
+	if c.HTTP.Proxy != "" {
+		if u, err := url.Parse(c.HTTP.Proxy); err != nil || u.Scheme == "" || u.Host == "" {
+			add("http.proxy", "must be a URL such as \"http://proxy.example.com:3128\"")
+		}
+	}
+
+	// Policy
+	filters := map[string]FilterConfig{
+		"policy.tools":     c.Policy.Tools,
+		"policy.resources": c.Policy.Resources,
+		"policy.prompts":   c.Policy.Prompts,
+	}
+	for _, section := range []string{"policy.tools", "policy.resources", "policy.prompts"} {
+		filter := filters[section]
+		for i, pattern := range filter.Enabled {
+			if _, err := path.Match(pattern, ""); err != nil {
+				add(fmt.Sprintf("%s.enabled[%d]", section, i), "invalid pattern %q", pattern)
+			}
+		}
+		for i, pattern := range filter.Disabled {
+			if _, err := path.Match(pattern, ""); err != nil {
+				add(fmt.Sprintf("%s.disabled[%d]", section, i), "invalid pattern %q", pattern)
+			}
+		}
+	}
+
+	// Scope
+	for i, pattern := range c.Scope.Tags {
+		if _, err := path.Match(pattern, ""); err != nil {
+			add(fmt.Sprintf("scope.tags[%d]", i), "invalid pattern %q", pattern)
+		}
+	}
+
+	// Audit
+	if c.Audit.MaxSizeMB < 0 {
+		add("audit.max_size_mb", "must not be negative")
+	}
+	if c.Audit.MaxBackups < 0 {
+		add("audit.max_backups", "must not be negative")
+	}
+	switch c.Audit.Syslog.Network {
+	case "", "udp", "tcp", "unix", "unixgram":
+	default:
+		add("audit.syslog.network", "must be \"udp\", \"tcp\", \"unix\" or \"unixgram\"")
+	}
+	if c.Audit.Syslog.Network != "" && c.Audit.Syslog.Address == "" {
+		add("audit.syslog.address", "required when audit.syslog.network is set")
+	}
+
+	// Approval
+	for i, rule := range c.Approval.Rules {
+		lists := map[string][]string{"tools": rule.Tools, "tags": rule.Tags}
+		for _, field := range []string{"tools", "tags"} {
+			for j, pattern := range lists[field] {
+				if _, err := path.Match(pattern, ""); err != nil {
+					add(fmt.Sprintf("approval.rules[%d].%s[%d]", i, field, j), "invalid pattern %q", pattern)
+				}
+			}
+		}
+	}
+	for i, token := range c.Approval.Tokens {
+		path := fmt.Sprintf("approval.tokens[%d]", i)
+		if token.Identity == "" {
+			add(path+".identity", "required field is missing")
+		}
+		if token.Token == "" {
+			add(path+".token", "required field is missing")
+		} else if err := checkSecretReference(token.Token); err != nil {
+			add(path+".token", "%v", err)
+		}
+	}
+	if len(c.Approval.Rules) > 0 && len(c.Approval.Tokens) == 0 && c.Approval.Listen != "" && !IsLoopbackAddress(c.Approval.Listen) {
+		add("approval.tokens", "required when approval.listen is not a loopback address")
+	}
+	if c.Approval.Timeout != "" {
+		if timeout, err := time.ParseDuration(c.Approval.Timeout); err != nil || timeout <= 0 {
+			add("approval.timeout", "must be a positive duration such as \"1h\"")
+		}
+	}
+	if c.Approval.Retention != "" {
+		if retention, err := time.ParseDuration(c.Approval.Retention); err != nil || retention <= 0 {
+			add("approval.retention", "must be a positive duration such as \"24h\"")
+		}
+	}
+
+	// Quotas
+	if c.Quotas.MaxResourcesPerCall < 0 {
+		add("quotas.max_resources_per_call", "must not be negative")
+	}
+	quotaLimits := map[string]QuotaLimits{"session": c.Quotas.Session, "caller": c.Quotas.Caller}
+	for _, scope := range []string{"session", "caller"} {
+		limits := quotaLimits[scope]
+		prefix := "quotas." + scope + "."
+		if limits.CallsPerMinute < 0 {
+			add(prefix+"calls_per_minute", "must not be negative")
+		}
+		if limits.MutationsPerHour < 0 {
+			add(prefix+"mutations_per_hour", "must not be negative")
+		}
+		patterns := make([]string, 0, len(limits.Tools))
+		for pattern := range limits.Tools {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+		for _, pattern := range patterns {
+			limit := limits.Tools[pattern]
+			if _, err := path.Match(pattern, ""); err != nil {
+				add(prefix+"tools."+pattern, "invalid pattern %q", pattern)
+			} else if limit < 0 {
+				add(prefix+"tools."+pattern, "must not be negative")
+			}
+		}
+	}
+
+	// Roles
+	roleNames := make([]string, 0, len(c.Roles))
+	for name := range c.Roles {
+		roleNames = append(roleNames, name)
+	}
+	sort.Strings(roleNames)
+	for _, name := range roleNames {
+		role := c.Roles[name]
+		prefix := "roles." + name + "."
+		if len(role.Identities) == 0 {
+			add(prefix+"identities", "required field is missing")
+		}
+		lists := map[string][]string{
+			"identities": role.Identities,
+			"tools":      role.Tools,
+			"regions":    role.Regions,
+			"projects":   role.Projects,
+			"tags":       role.Tags,
+		}
+		for _, field := range []string{"identities", "tools", "regions", "projects", "tags"} {
+			for i, pattern := range lists[field] {
+				if _, err := path.Match(pattern, ""); err != nil {
+					add(fmt.Sprintf("%s%s[%d]", prefix, field, i), "invalid pattern %q", pattern)
+				}
+			}
+		}
+	}
+
+	// Auth
+	seen := make(map[string]bool)
+	for i, token := range c.Auth.Tokens {
+		path := fmt.Sprintf("auth.tokens[%d]", i)
+		if token.Identity == "" {
+			add(path+".identity", "required field is missing")
+		}
+		if token.Token == "" {
+			add(path+".token", "required field is missing")
+		} else if seen[token.Token] {
+			add(path+".token", "duplicate token")
+		} else if err := checkSecretReference(token.Token); err != nil {
+			add(path+".token", "%v", err)
+		}
+		seen[token.Token] = true
+	}
+
+	return problems
+}
+
+// problems returns the problems of a single profile, with paths relative to the profile
+func (p *Profile) problems(c *Config) []Problem {
+	var problems []Problem
+	required := map[string]string{
+		"region":      p.Region,
+		"project_id":  p.ProjectID,
+		"public_key":  p.PublicKey,
+		"private_key": p.PrivateKey,
+	}
+	for _, field := range []string{"region", "project_id", "public_key", "private_key"} {
+		if required[field] == "" {
+			problems = append(problems, Problem{Path: field, Message: "required field is missing"})
+		}
+	}
+	required["security_token"] = p.SecurityToken
+	for _, field := range []string{"public_key", "private_key", "security_token"} {
+		if err := checkSecretReference(required[field]); err != nil {
+			problems = append(problems, Problem{Path: field, Message: err.Error()})
+		}
+	}
+
+	// The default project must be reachable when an allow-list is configured
+	if p.ProjectID != "" && len(p.Projects) > 0 && !p.IsProjectAllowed(p.ProjectID) {
+		problems = append(problems, Problem{
+			Path:    "project_id",
+			Message: fmt.Sprintf("project %s is not in the projects allow-list", p.ProjectID),
+		})
+	}
+
+	if p.Region != "" && !c.IsRegionAllowed(p.Region) {
+		problems = append(problems, Problem{
+			Path:    "region",
+			Message: fmt.Sprintf("region %s is not in regions.allowed", p.Region),
+		})
+	}
+
+	if p.BaseURL != "" && !strings.HasPrefix(p.BaseURL, "http://") && !strings.HasPrefix(p.BaseURL, "https://") {
+		problems = append(problems, Problem{Path: "base_url", Message: "must be an http:// or https:// URL"})
+	}
+
+	return problems
+}
+
+// IsRegionAllowed reports whether profiles may use the region
+func (c *Config) IsRegionAllowed(region string) bool {
+	if len(c.Regions.Allowed) == 0 {
+		return true
+	}
+	for _, allowed := range c.Regions.Allowed {
+		if allowed == region {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLoopbackAddress reports whether a listen address such as "127.0.0.1:8090" only accepts local connections
+func IsLoopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// mergeProblems appends semantic problems, skipping fields that already have a problem
+func mergeProblems(problems, more []Problem) []Problem {
+	reported := make(map[string]bool)
+	for _, problem := range problems {
+		reported[problem.Path] = true
+	}
+	for _, problem := range more {
+		if !reported[problem.Path] {
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
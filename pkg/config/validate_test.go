@@ -0,0 +1,155 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const validYAML = `region: cn-bj2
+project_id: org-test
+public_key: env:UCLOUD_PUBLIC_KEY
+private_key: file:/run/secrets/ucloud_private_key
+server:
+  port: 8080
+`
+
+func TestValidateFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		// wantProblems lists "line: path: message" fragments that must be reported
+		wantProblems []string
+	}{
+		{
+			name:     "valid yaml",
+			filename: "config.yaml",
+			content:  validYAML,
+		},
+		{
+			name:     "valid toml",
+			filename: "config.toml",
+			content: `region = "cn-bj2"
+project_id = "org-test"
+public_key = "pub"
+private_key = "priv"
+`,
+		},
+		{
+			name:         "unknown field",
+			filename:     "config.yaml",
+			content:      validYAML + "colour: blue\n",
+			wantProblems: []string{"line 7: colour: unknown field"},
+		},
+		{
+			name:         "wrong type",
+			filename:     "config.yaml",
+			content:      strings.Replace(validYAML, "port: 8080", "port: eighty", 1),
+			wantProblems: []string{"line 6: server.port: expected an integer"},
+		},
+		{
+			name:         "missing key",
+			filename:     "config.json",
+			content:      `{"region": "cn-bj2", "project_id": "org-test", "public_key": "pub"}`,
+			wantProblems: []string{"private_key: required field is missing"},
+		},
+		{
+			name:         "exec reference without argv list",
+			filename:     "config.yaml",
+			content:      strings.Replace(validYAML, "env:UCLOUD_PUBLIC_KEY", "exec:/usr/local/bin/helper read", 1),
+			wantProblems: []string{"line 3: public_key: credential helper command must be a JSON list"},
+		},
+		{
+			name:         "empty env reference",
+			filename:     "config.yaml",
+			content:      strings.Replace(validYAML, "env:UCLOUD_PUBLIC_KEY", "\"env:\"", 1),
+			wantProblems: []string{"public_key: env: reference needs a variable name"},
+		},
+		{
+			name:     "invalid patterns and durations",
+			filename: "config.yaml",
+			content:  validYAML + "secret_cache_ttl: soon\npolicy:\n  tools:\n    disabled: [\"[\"]\n",
+			wantProblems: []string{
+				"secret_cache_ttl: must be a positive duration",
+				"policy.tools.disabled[0]: invalid pattern",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), tt.filename)
+			if err := os.WriteFile(filename, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			err := ValidateFile(filename)
+			if len(tt.wantProblems) == 0 {
+				if err != nil {
+					t.Fatalf("ValidateFile: %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("ValidateFile error = %v, want a *ValidationError", err)
+			}
+			for _, want := range tt.wantProblems {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("problems do not contain %q:\n%v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateFileIgnoresEnvironment(t *testing.T) {
+	t.Setenv("UCLOUD_PRIVATE_KEY", "from-env")
+	t.Setenv("HOME", t.TempDir())
+
+	filename := filepath.Join(t.TempDir(), "config.json")
+	content := `{"region": "cn-bj2", "project_id": "org-test", "public_key": "pub"}`
+	if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadConfig(filename); err != nil {
+		t.Fatalf("LoadConfig should fill private_key from the environment: %v", err)
+	}
+	if err := ValidateFile(filename); err == nil || !strings.Contains(err.Error(), "private_key: required field is missing") {
+		t.Errorf("ValidateFile error = %v, want private_key reported missing", err)
+	}
+}
+
+func TestValidateFileIgnoresMissingFiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	missing := filepath.Join(t.TempDir(), "missing")
+	content := `region: cn-bj2
+project_id: org-test
+public_key: pub
+private_key: priv
+http:
+  ca_bundle: ` + missing + `/ca.pem
+scope:
+  instance_ids_file: ` + missing + `/instances.txt
+`
+	if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ValidateFile(filename); err != nil {
+		t.Errorf("ValidateFile should not check files on this host: %v", err)
+	}
+	_, err := LoadConfig(filename)
+	for _, want := range []string{"http.ca_bundle", "scope.instance_ids_file"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadConfig error = %v, want %s reported missing", err, want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"time"
+)
+
+// fileState identifies a version of a file on disk
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// statFile returns the current state of a file
+func statFile(filename string) fileState {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, modTime: info.ModTime(), size: info.Size()}
+}
+
+// WatchFile polls a file every interval and calls onChange when it is created,
+// modified or removed. It returns when the context is cancelled.
+func WatchFile(ctx context.Context, filename string, interval time.Duration, onChange func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := statFile(filename)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := statFile(filename)
+			if current != last {
+				last = current
+				onChange()
+			}
+		}
+	}
+}
+
+// restartSections are the configuration sections that a reload does not apply.
+// Profiles, credentials, HTTP settings and the scope take effect on reload.
+var restartSections = []struct {
+	name string
+	get  func(c *Config) interface{}
+}{
+	{"server", func(c *Config) interface{} { return c.Server }},
+	{"transport", func(c *Config) interface{} { return c.Transport }},
+	{"auth", func(c *Config) interface{} { return c.Auth }},
+	{"policy", func(c *Config) interface{} { return c.Policy }},
+	{"audit", func(c *Config) interface{} { return c.Audit }},
+	{"approval", func(c *Config) interface{} { return c.Approval }},
+	{"quotas", func(c *Config) interface{} { return c.Quotas }},
+	{"roles", func(c *Config) interface{} { return c.Roles }},
+}
+
+// RestartRequired returns the sections that differ between the running and a
+// reloaded configuration but only take effect when the server restarts
+func RestartRequired(running, reloaded *Config) []string {
+	var changed []string
+	for _, section := range restartSections {
+		if !reflect.DeepEqual(section.get(running), section.get(reloaded)) {
+			changed = append(changed, section.name)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRestartRequired(t *testing.T) {
+	running := &Config{
+		Profile: Profile{Region: "cn-bj2", PublicKey: "old"},
+		Server:  ServerConfig{Port: 8080},
+		Auth:    AuthConfig{Tokens: []AuthToken{{Identity: "alice", Token: "env:ALICE_TOKEN"}}},
+	}
+
+	tests := []struct {
+		name   string
+		change func(c *Config)
+		want   []string
+	}{
+		{"unchanged", func(c *Config) {}, nil},
+		{"credentials", func(c *Config) { c.PublicKey = "new" }, nil},
+		{"scope", func(c *Config) { c.Scope.Tags = []string{"web"} }, nil},
+		{"server and transport", func(c *Config) {
+			c.Server.Port = 9090
+			c.Transport.BaseURL = "https://mcp.example.com"
+		}, []string{"server", "transport"}},
+		{"revoked token", func(c *Config) { c.Auth.Tokens = nil }, []string{"auth"}},
+		{"policy and roles", func(c *Config) {
+			c.Policy.ReadOnly = true
+			c.Roles = map[string]RoleConfig{"oncall": {Identities: []string{"alice"}}}
+		}, []string{"policy", "roles"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reloaded := *running
+			reloaded.Auth.Tokens = append([]AuthToken(nil), running.Auth.Tokens...)
+			tt.change(&reloaded)
+			if got := RestartRequired(running, &reloaded); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RestartRequired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,451 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/ucloud/ucloud-mcp-server/pkg/audit"
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+)
+
+// Approval defaults
+const (
+	DefaultApprovalListen    = "127.0.0.1:8090"
+	defaultApprovalTimeout   = time.Hour
+	defaultApprovalRetention = 24 * time.Hour
+)
+
+// errSelfApproval is returned when a caller tries to approve its own operation
+var errSelfApproval = errors.New("operations cannot be approved by the identity that requested them")
+
+// Operation statuses
+const (
+	StatusPendingApproval = "pending_approval"
+	StatusRunning         = "running"
+	StatusSucceeded       = "succeeded"
+	StatusFailed          = "failed"
+	StatusRejected        = "rejected"
+	StatusExpired         = "expired"
+)
+
+// approvedKey is the context key of the operation ID when an approved operation runs
+type approvedKey struct{}
+
+// approvedOperation returns the operation ID when the tool call runs an approved operation
+func approvedOperation(ctx context.Context) string {
+	id, _ := ctx.Value(approvedKey{}).(string)
+	return id
+}
+
+// operation is a mutating tool call waiting for, or executed after, approval
+type operation struct {
+	ID         string                 `json:"operation_id"`
+	Tool       string                 `json:"tool"`
+	Identity   string                 `json:"identity"`
+	Arguments  map[string]interface{} `json:"arguments,omitempty"`
+	Targets    []target               `json:"targets,omitempty"`
+	Status     string                 `json:"status"`
+	Approver   string                 `json:"approver,omitempty"`
+	Result     string                 `json:"result,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+	ExpiresAt  time.Time              `json:"expires_at"`
+	DecidedAt  *time.Time             `json:"decided_at,omitempty"`
+	FinishedAt *time.Time             `json:"finished_at,omitempty"`
+
+	// ctx and request replay the tool call once approved
+	ctx     context.Context
+	request mcp.CallToolRequest
+}
+
+// approvalQueue holds mutating tool calls until an approver decides on them
+type approvalQueue struct {
+	mu         sync.Mutex
+	rules      []config.ApprovalRule
+	timeout    time.Duration
+	retention  time.Duration
+	listen     string
+	approvers  *authenticator
+	audit      *audit.Logger
+	operations map[string]*operation
+	handlers   map[string]server.ToolHandlerFunc
+}
+
+// newApprovalQueue creates the approval queue described by the configuration
+func newApprovalQueue(cfg config.ApprovalConfig, auditLogger *audit.Logger) (*approvalQueue, error) {
+	q := &approvalQueue{
+		rules:      cfg.Rules,
+		timeout:    defaultApprovalTimeout,
+		retention:  defaultApprovalRetention,
+		listen:     cfg.Listen,
+		audit:      auditLogger,
+		operations: make(map[string]*operation),
+		handlers:   make(map[string]server.ToolHandlerFunc),
+	}
+
+	if q.listen == "" {
+		q.listen = DefaultApprovalListen
+	}
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid approval timeout: %v", err)
+		}
+		q.timeout = timeout
+	}
+	if cfg.Retention != "" {
+		retention, err := time.ParseDuration(cfg.Retention)
+		if err != nil {
+			return nil, fmt.Errorf("invalid approval retention: %v", err)
+		}
+		q.retention = retention
+	}
+
+	// Approver identities come from their tokens
+	approvers, err := newAuthenticator(config.AuthConfig{Tokens: cfg.Tokens})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve approval tokens: %v", err)
+	}
+	q.approvers = approvers
+	if len(q.rules) > 0 && !approvers.enabled() && !config.IsLoopbackAddress(q.listen) {
+		return nil, fmt.Errorf("approval tokens are required when the approval endpoint listens on %s", q.listen)
+	}
+
+	return q, nil
+}
+
+// enabled reports whether any tool call can require approval
+func (q *approvalQueue) enabled() bool {
+	return q != nil && len(q.rules) > 0
+}
+
+// register records the full handler chain of a mutating tool, used to run it once approved
+func (q *approvalQueue) register(tool string, handler server.ToolHandlerFunc) {
+	q.handlers[tool] = handler
+}
+
+// requires reports whether a tool call affecting the targets needs approval
+func (q *approvalQueue) requires(tool string, targets []target) bool {
+	if !q.enabled() {
+		return false
+	}
+	for _, rule := range q.rules {
+		if !matchesOrEmpty(rule.Tools, tool) {
+			continue
+		}
+		if len(rule.Tags) == 0 {
+			return true
+		}
+		for _, t := range targets {
+			if matchesAny(rule.Tags, t.Tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// park queues a tool call until it is approved or rejected
+func (q *approvalQueue) park(ctx context.Context, request mcp.CallToolRequest, targets []target) *operation {
+	now := time.Now().UTC()
+	op := &operation{
+		ID:        newOperationID(),
+		Tool:      request.Params.Name,
+		Identity:  IdentityFromContext(ctx),
+		Arguments: request.Params.Arguments,
+		Targets:   targets,
+		Status:    StatusPendingApproval,
+		CreatedAt: now,
+		ExpiresAt: now.Add(q.timeout),
+		ctx:       context.WithoutCancel(ctx),
+		request:   request,
+	}
+
+	q.mu.Lock()
+	q.prune()
+	q.operations[op.ID] = op
+	q.mu.Unlock()
+
+	log.Printf("Operation %s (%s by %s) is waiting for approval", op.ID, op.Tool, op.Identity)
+	return op
+}
+
+// get returns a snapshot of an operation
+func (q *approvalQueue) get(id string) (operation, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.prune()
+	op, ok := q.operations[id]
+	if !ok {
+		return operation{}, false
+	}
+	return op.snapshot(), true
+}
+
+// list returns snapshots of all operations, newest first
+func (q *approvalQueue) list() []operation {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.prune()
+	ops := make([]operation, 0, len(q.operations))
+	for _, op := range q.operations {
+		ops = append(ops, op.snapshot())
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].CreatedAt.After(ops[j].CreatedAt)
+	})
+	return ops
+}
+
+// decide approves or rejects a pending operation. Approved operations run in the background.
+// Callers may reject, but not approve, their own operations.
+func (q *approvalQueue) decide(id, approver string, approve bool) (operation, error) {
+	q.mu.Lock()
+	op, ok := q.operations[id]
+	if !ok {
+		q.mu.Unlock()
+		return operation{}, fmt.Errorf("operation %s not found", id)
+	}
+	q.expire(op)
+	if op.Status != StatusPendingApproval {
+		q.mu.Unlock()
+		return operation{}, fmt.Errorf("operation %s is %s", id, op.Status)
+	}
+	if approve && approver == op.Identity {
+		q.mu.Unlock()
+		return operation{}, errSelfApproval
+	}
+
+	now := time.Now().UTC()
+	op.Approver = approver
+	op.DecidedAt = &now
+	op.Status = StatusRejected
+	decision := "reject"
+	if approve {
+		op.Status = StatusRunning
+		decision = "approve"
+	}
+	snapshot := op.snapshot()
+	q.mu.Unlock()
+
+	log.Printf("Operation %s %sd by %s", id, decision, approver)
+	q.audit.Log(audit.Record{
+		Time:     now,
+		Identity: approver,
+		Tool:     "approval",
+		Arguments: map[string]interface{}{
+			"operation_id": id,
+			"tool":         op.Tool,
+			"decision":     decision,
+		},
+		Status: audit.StatusOK,
+	})
+
+	if approve {
+		go q.run(op)
+	}
+	return snapshot, nil
+}
+
+// run replays an approved tool call through its full handler chain
+func (q *approvalQueue) run(op *operation) {
+	handler, ok := q.handlers[op.Tool]
+	var result *mcp.CallToolResult
+	var err error
+	if ok {
+		ctx := context.WithValue(op.ctx, approvedKey{}, op.ID)
+		result, err = handler(ctx, op.request)
+	} else {
+		err = fmt.Errorf("tool %s is not registered", op.Tool)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now().UTC()
+	op.FinishedAt = &now
+	switch {
+	case err != nil:
+		op.Status = StatusFailed
+		op.Result = err.Error()
+	case result != nil && result.IsError:
+		op.Status = StatusFailed
+		op.Result = resultText(result)
+	default:
+		op.Status = StatusSucceeded
+		if result != nil {
+			op.Result = resultText(result)
+		}
+	}
+	log.Printf("Operation %s %s", op.ID, op.Status)
+}
+
+// expire marks a pending operation as expired once its timeout has passed.
+// The queue must be locked.
+func (q *approvalQueue) expire(op *operation) {
+	if op.Status == StatusPendingApproval && time.Now().After(op.ExpiresAt) {
+		op.Status = StatusExpired
+	}
+}
+
+// prune removes operations that were decided, finished or expired longer than the
+// retention period ago. The queue must be locked.
+func (q *approvalQueue) prune() {
+	cutoff := time.Now().Add(-q.retention)
+	for id, op := range q.operations {
+		q.expire(op)
+		var settled time.Time
+		switch {
+		case op.FinishedAt != nil:
+			settled = *op.FinishedAt
+		case op.Status == StatusRejected:
+			settled = *op.DecidedAt
+		case op.Status == StatusExpired:
+			settled = op.ExpiresAt
+		default:
+			// Pending and running operations are kept
+			continue
+		}
+		if settled.Before(cutoff) {
+			delete(q.operations, id)
+		}
+	}
+}
+
+// snapshot returns a copy of the operation with sensitive arguments redacted
+func (op *operation) snapshot() operation {
+	return operation{
+		ID:         op.ID,
+		Tool:       op.Tool,
+		Identity:   op.Identity,
+		Arguments:  audit.Redact(op.Arguments),
+		Targets:    op.Targets,
+		Status:     op.Status,
+		Approver:   op.Approver,
+		Result:     op.Result,
+		CreatedAt:  op.CreatedAt,
+		ExpiresAt:  op.ExpiresAt,
+		DecidedAt:  op.DecidedAt,
+		FinishedAt: op.FinishedAt,
+	}
+}
+
+// newOperationID returns a random operation ID
+func newOperationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("op-%d", time.Now().UnixNano())
+	}
+	return "op-" + hex.EncodeToString(b)
+}
+
+// pendingResult tells the agent that its call is waiting for approval
+func pendingResult(op *operation) (*mcp.CallToolResult, error) {
+	response := map[string]interface{}{
+		"operation_id": op.ID,
+		"status":       op.Status,
+		"expires_at":   op.ExpiresAt.Format(time.RFC3339),
+		"message": fmt.Sprintf("%s requires human approval. Poll get_operation_status with operation_id %s for the result.",
+			op.Tool, op.ID),
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal operation: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// serve runs the approval endpoint
+func (q *approvalQueue) serve() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /operations", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, q.list())
+	})
+	mux.HandleFunc("GET /operations/{id}", func(w http.ResponseWriter, r *http.Request) {
+		op, ok := q.get(r.PathValue("id"))
+		if !ok {
+			http.Error(w, "operation not found", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, op)
+	})
+	mux.HandleFunc("POST /operations/{id}/approve", func(w http.ResponseWriter, r *http.Request) {
+		q.handleDecision(w, r, true)
+	})
+	mux.HandleFunc("POST /operations/{id}/reject", func(w http.ResponseWriter, r *http.Request) {
+		q.handleDecision(w, r, false)
+	})
+
+	log.Printf("Approval endpoint listening on %s", q.listen)
+	return http.ListenAndServe(q.listen, q.authorizeApprover(mux))
+}
+
+// handleDecision approves or rejects the operation named in the URL. The approver
+// is the identity of the approval token; without tokens, which is only allowed on
+// a loopback address, approvers name themselves with the approver query parameter.
+func (q *approvalQueue) handleDecision(w http.ResponseWriter, r *http.Request, approve bool) {
+	approver := IdentityFromContext(r.Context())
+	if !q.approvers.enabled() {
+		approver = r.URL.Query().Get("approver")
+		if approver == "" {
+			http.Error(w, "approver is required", http.StatusBadRequest)
+			return
+		}
+	}
+
+	op, err := q.decide(r.PathValue("id"), approver, approve)
+	if errors.Is(err, errSelfApproval) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, op)
+}
+
+// authorizeApprover requires an approval token, when tokens are configured, and
+// stores the approver identity in the request context
+func (q *approvalQueue) authorizeApprover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if q.approvers.enabled() {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			identity, ok := q.approvers.identify(token)
+			if token == "" || !ok {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			r = r.WithContext(context.WithValue(r.Context(), identityKey{}, identity))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeJSON writes an indented JSON response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	jsonData, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(jsonData)
+	w.Write([]byte("\n"))
+}
@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+)
+
+// newTestQueue returns a queue requiring approval for every tool, with no tokens on a loopback address
+func newTestQueue(t *testing.T) *approvalQueue {
+	t.Helper()
+	q, err := newApprovalQueue(config.ApprovalConfig{Rules: []config.ApprovalRule{{}}}, nil)
+	if err != nil {
+		t.Fatalf("newApprovalQueue: %v", err)
+	}
+	return q
+}
+
+// parkAs queues a tool call requested by identity
+func parkAs(q *approvalQueue, identity string) *operation {
+	ctx := context.WithValue(context.Background(), identityKey{}, identity)
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "reboot_instance"
+	return q.park(ctx, request, nil)
+}
+
+func TestApprovalQueueRejectsSelfApproval(t *testing.T) {
+	q := newTestQueue(t)
+
+	op := parkAs(q, "alice")
+	if _, err := q.decide(op.ID, "alice", true); !errors.Is(err, errSelfApproval) {
+		t.Errorf("self-approval error = %v, want %v", err, errSelfApproval)
+	}
+	if _, err := q.decide(op.ID, "alice", false); err != nil {
+		t.Errorf("requesters may reject their own operations: %v", err)
+	}
+
+	op = parkAs(q, "alice")
+	decided, err := q.decide(op.ID, "bob", true)
+	if err != nil {
+		t.Fatalf("approval by another identity: %v", err)
+	}
+	if decided.Approver != "bob" || decided.Status != StatusRunning {
+		t.Errorf("decided operation = %+v, want running and approved by bob", decided)
+	}
+}
+
+func TestApprovalQueuePrunesSettledOperations(t *testing.T) {
+	q := newTestQueue(t)
+	q.retention = time.Minute
+	old := time.Now().Add(-2 * time.Minute)
+
+	pending := parkAs(q, "alice")
+	rejected := parkAs(q, "alice")
+	if _, err := q.decide(rejected.ID, "bob", false); err != nil {
+		t.Fatal(err)
+	}
+	rejected.DecidedAt = &old
+	expired := parkAs(q, "alice")
+	expired.ExpiresAt = old
+	recent := parkAs(q, "alice")
+	if _, err := q.decide(recent.ID, "bob", false); err != nil {
+		t.Fatal(err)
+	}
+
+	ops := q.list()
+	kept := make(map[string]bool)
+	for _, op := range ops {
+		kept[op.ID] = true
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{"pending", pending.ID, true},
+		{"rejected long ago", rejected.ID, false},
+		{"expired long ago", expired.ID, false},
+		{"recently rejected", recent.ID, true},
+	}
+	for _, tt := range tests {
+		if kept[tt.id] != tt.want {
+			t.Errorf("%s operation kept = %v, want %v", tt.name, kept[tt.id], tt.want)
+		}
+	}
+}
+
+func TestApprovalQueueRequiresTokensOffLoopback(t *testing.T) {
+	tests := []struct {
+		listen  string
+		tokens  []config.AuthToken
+		wantErr bool
+	}{
+		{"127.0.0.1:8090", nil, false},
+		{"localhost:8090", nil, false},
+		{"[::1]:8090", nil, false},
+		{"0.0.0.0:8090", nil, true},
+		{":8090", nil, true},
+		{"0.0.0.0:8090", []config.AuthToken{{Identity: "alice", Token: "secret"}}, false},
+	}
+
+	for _, tt := range tests {
+		cfg := config.ApprovalConfig{Rules: []config.ApprovalRule{{}}, Listen: tt.listen, Tokens: tt.tokens}
+		_, err := newApprovalQueue(cfg, nil)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("newApprovalQueue(listen %q, %d tokens) error = %v, wantErr %v", tt.listen, len(tt.tokens), err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/ucloud/ucloud-mcp-server/pkg/audit"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+)
+
+// recorderKey is the context key of the API call recorder of a tool call
+type recorderKey struct{}
+
+// callRecorder collects the UCloud API calls made while handling a tool call
+type callRecorder struct {
+	mu    sync.Mutex
+	calls []ucloud.APICall
+}
+
+// add records an API call
+func (r *callRecorder) add(call ucloud.APICall) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+// list returns the recorded API calls
+func (r *callRecorder) list() []ucloud.APICall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ucloud.APICall(nil), r.calls...)
+}
+
+// observeClient returns a client that records its API calls for the audit log of the tool call
+func observeClient(ctx context.Context, client *ucloud.UCloudClient) *ucloud.UCloudClient {
+	recorder, ok := ctx.Value(recorderKey{}).(*callRecorder)
+	if !ok {
+		return client
+	}
+	return client.WithObserver(recorder.add)
+}
+
+// audited wraps a tool handler so every call is written to the audit log,
+// including calls rejected by access control
+func audited(logger *audit.Logger, tool string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if logger == nil {
+		return next
+	}
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		recorder := &callRecorder{}
+		start := time.Now()
+
+		result, err := next(context.WithValue(ctx, recorderKey{}, recorder), request)
+
+		record := audit.Record{
+			Time:        start.UTC(),
+			Identity:    IdentityFromContext(ctx),
+			SessionID:   SessionIDFromContext(ctx),
+			Tool:        tool,
+			OperationID: approvedOperation(ctx),
+			Arguments:   request.Params.Arguments,
+			APICalls:    recorder.list(),
+			Status:      audit.StatusOK,
+			DurationMS:  time.Since(start).Milliseconds(),
+		}
+		switch {
+		case err != nil:
+			record.Status = audit.StatusError
+			record.Error = err.Error()
+		case result != nil && result.IsError:
+			record.Status = audit.StatusError
+			record.Error = resultText(result)
+		}
+		logger.Log(record)
+
+		return result, err
+	}
+}
+
+// resultText returns the text content of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
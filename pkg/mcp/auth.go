@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+)
+
+// AnonymousIdentity is the identity of callers not authenticated with a token
+const AnonymousIdentity = "anonymous"
+
+// identityKey is the context key of the authenticated caller identity
+type identityKey struct{}
+
+// sessionKey is the context key of the MCP session ID
+type sessionKey struct{}
+
+// IdentityFromContext returns the authenticated caller of a request
+func IdentityFromContext(ctx context.Context) string {
+	if identity, ok := ctx.Value(identityKey{}).(string); ok {
+		return identity
+	}
+	return AnonymousIdentity
+}
+
+// SessionIDFromContext returns the MCP session ID of a request, if known
+func SessionIDFromContext(ctx context.Context) string {
+	sessionID, _ := ctx.Value(sessionKey{}).(string)
+	return sessionID
+}
+
+// authenticator maps bearer tokens to caller identities
+type authenticator struct {
+	tokens map[string]string
+}
+
+// newAuthenticator resolves the configured tokens, which may be secret references
+func newAuthenticator(cfg config.AuthConfig) (*authenticator, error) {
+	a := &authenticator{tokens: make(map[string]string)}
+	for _, entry := range cfg.Tokens {
+		token, _, err := config.ResolveSecret(entry.Token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve token of %s: %v", entry.Identity, err)
+		}
+		a.tokens[token] = entry.Identity
+	}
+	return a, nil
+}
+
+// enabled reports whether callers must authenticate
+func (a *authenticator) enabled() bool {
+	return len(a.tokens) > 0
+}
+
+// identify returns the identity of a bearer token
+func (a *authenticator) identify(token string) (string, bool) {
+	for candidate, identity := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			return identity, true
+		}
+	}
+	return "", false
+}
+
+// wrap puts a handler behind the middleware and logs whether callers must authenticate
+func (a *authenticator) wrap(next http.Handler) http.Handler {
+	if a.enabled() {
+		log.Printf("Bearer token authentication enabled for %d identities", len(a.tokens))
+	} else {
+		log.Printf("Bearer token authentication disabled, SSE clients are %s", AnonymousIdentity)
+	}
+	return a.middleware(next)
+}
+
+// middleware authenticates HTTP requests and stores the caller identity and
+// session ID in the request context for tool handlers
+func (a *authenticator) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity := AnonymousIdentity
+
+		if a.enabled() {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			var ok bool
+			identity, ok = a.identify(token)
+			if token == "" || !ok {
+				log.Printf("Rejected unauthenticated request from %s to %s", r.RemoteAddr, r.URL.Path)
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), identityKey{}, identity)
+		if sessionID := r.URL.Query().Get("sessionId"); sessionID != "" {
+			ctx = context.WithValue(ctx, sessionKey{}, sessionID)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authenticatedOrLocal serves a handler to authenticated callers or, when
+// authentication is disabled, only to clients connecting from a loopback address
+func (a *authenticator) authenticatedOrLocal(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.enabled() && !isLoopbackRequest(r) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLoopbackRequest reports whether a request comes from a loopback address
+func isLoopbackRequest(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
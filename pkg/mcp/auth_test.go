@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+)
+
+func TestAuthenticatorMiddleware(t *testing.T) {
+	withTokens, err := newAuthenticator(config.AuthConfig{Tokens: []config.AuthToken{{Identity: "alice", Token: "secret"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	withoutTokens, err := newAuthenticator(config.AuthConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		auth         *authenticator
+		token        string
+		want         int
+		wantIdentity string
+	}{
+		{"no auth", withoutTokens, "", http.StatusOK, AnonymousIdentity},
+		{"no auth, token ignored", withoutTokens, "secret", http.StatusOK, AnonymousIdentity},
+		{"valid token", withTokens, "secret", http.StatusOK, "alice"},
+		{"missing token", withTokens, "", http.StatusUnauthorized, ""},
+		{"wrong token", withTokens, "guess", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var identity, sessionID string
+			handler := tt.auth.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				identity = IdentityFromContext(r.Context())
+				sessionID = SessionIDFromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/message?sessionId=session-1", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if identity != tt.wantIdentity {
+				t.Errorf("identity = %q, want %q", identity, tt.wantIdentity)
+			}
+			if tt.want == http.StatusOK && sessionID != "session-1" {
+				t.Errorf("session ID = %q, want %q", sessionID, "session-1")
+			}
+		})
+	}
+}
+
+func TestAuthenticatedOrLocal(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	withTokens, err := newAuthenticator(config.AuthConfig{Tokens: []config.AuthToken{{Identity: "alice", Token: "secret"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	withoutTokens, err := newAuthenticator(config.AuthConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		auth       *authenticator
+		remoteAddr string
+		token      string
+		want       int
+	}{
+		{"no auth, loopback", withoutTokens, "127.0.0.1:51234", "", http.StatusOK},
+		{"no auth, remote", withoutTokens, "203.0.113.7:51234", "", http.StatusForbidden},
+		{"auth, valid token", withTokens, "203.0.113.7:51234", "secret", http.StatusOK},
+		{"auth, missing token", withTokens, "127.0.0.1:51234", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := tt.auth.middleware(tt.auth.authenticatedOrLocal(ok))
+			req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+)
+
+// target is a UCloud resource affected by a mutating tool call
+type target struct {
+	ID  string `json:"id"`
+	Tag string `json:"tag,omitempty"`
+}
+
+// change describes what a mutating tool call is about to do. Handlers resolve
+// and validate their targets first, then hand the change to applyChange.
+type change struct {
+	// Client is the client the change is made with
+	Client *ucloud.UCloudClient
+
+	// Targets are the resources the change affects
+	Targets []target
+
+	// Preview optionally describes the change in dry runs, e.g. as a diff
+	Preview interface{}
+
+	// Disruption, if set, describes the outage the change causes. Such changes
+	// only run when the call sets confirm to true.
+	Disruption string
+
+	// Run makes the change with the given client
+	Run func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error)
+}
+
+// applyChange makes a change. In dry-run mode it only reports the requests the
+// change would send; otherwise it refuses disruptive changes that were not
+// confirmed and parks the change if an approval rule matches.
+// Changes count against the mutation quotas when they run.
+func (h *Handlers) applyChange(ctx context.Context, request mcp.CallToolRequest, c change) (*mcp.CallToolResult, error) {
+	tool := request.Params.Name
+	if err := h.quotas.checkResources(tool, len(c.Targets)); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if h.dryRun || getBoolArg(request, "dry_run") {
+		return dryRunChange(request, c)
+	}
+
+	if c.Disruption != "" && !getBoolArg(request, "confirm") {
+		return mcp.NewToolResultError(fmt.Sprintf("%s. Call %s again with confirm set to true to proceed, "+
+			"or with dry_run to preview it.", c.Disruption, tool)), nil
+	}
+
+	if approvedOperation(ctx) == "" && h.approvals.requires(tool, c.Targets) {
+		op := h.approvals.park(ctx, request, c.Targets)
+		return pendingResult(op)
+	}
+
+	if err := h.quotas.checkMutation(ctx, tool); err != nil {
+		log.Printf("Rejected change by %s: %v", tool, err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return c.Run(c.Client)
+}
+
+// dryRunChange runs a change against a client that records its requests instead of sending them
+func dryRunChange(request mcp.CallToolRequest, c change) (*mcp.CallToolResult, error) {
+	recorder := &ucloud.DryRun{}
+	result, err := c.Run(c.Client.WithDryRun(recorder))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Dry run failed: %v", err)), nil
+	}
+	if result != nil && result.IsError {
+		return mcp.NewToolResultError(fmt.Sprintf("Dry run failed: %s", resultText(result))), nil
+	}
+
+	response := map[string]interface{}{
+		"dry_run":    true,
+		"tool":       request.Params.Name,
+		"project_id": c.Client.ProjectID(),
+		"region":     c.Client.Region(),
+		"targets":    c.Targets,
+		"requests":   recorder.Requests(),
+	}
+	if c.Preview != nil {
+		response["preview"] = c.Preview
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal dry run: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// jsonResult formats a response, such as the outcome of a change, as an indented JSON tool result
+func jsonResult(response map[string]interface{}) (*mcp.CallToolResult, error) {
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal result: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
@@ -0,0 +1,285 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+)
+
+// fakeAPI answers UCloud API requests with canned responses and records the
+// actions it was sent. Responses are keyed by action, or by action and
+// parameters in the form "DescribeFirewall?ResourceType=uhost", which take precedence.
+type fakeAPI struct {
+	responses map[string]string
+
+	mu      sync.Mutex
+	actions []string
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := requestParams(r)
+	action := params.Get("Action")
+
+	f.mu.Lock()
+	f.actions = append(f.actions, action)
+	f.mu.Unlock()
+
+	body, ok := f.responses[action]
+	if !ok {
+		body = `{"RetCode":0}`
+	}
+	for key, response := range f.responses {
+		if matchesRequest(key, params) {
+			body = response
+			break
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, body)
+}
+
+// sent returns the actions the API was sent
+func (f *fakeAPI) sent() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.actions...)
+}
+
+// matchesRequest reports whether a response key with parameters matches a request
+func matchesRequest(key string, params url.Values) bool {
+	action, query, found := strings.Cut(key, "?")
+	if !found || action != params.Get("Action") {
+		return false
+	}
+	want, err := url.ParseQuery(query)
+	if err != nil {
+		return false
+	}
+	for name := range want {
+		if params.Get(name) != want.Get(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// requestParams decodes the parameters of a form or JSON encoded API request
+func requestParams(r *http.Request) url.Values {
+	if strings.Contains(r.Header.Get("Content-Type"), "json") {
+		var decoded map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&decoded)
+		params := make(url.Values)
+		for name, value := range decoded {
+			params.Set(name, fmt.Sprint(value))
+		}
+		return params
+	}
+	r.ParseForm()
+	return r.Form
+}
+
+// testProfile returns a profile whose API is served by handler
+func testProfile(t *testing.T, handler http.Handler) config.Profile {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return config.Profile{
+		Region:     "cn-bj2",
+		ProjectID:  "org-test",
+		PublicKey:  "public",
+		PrivateKey: "private",
+		BaseURL:    server.URL,
+	}
+}
+
+// newTestHandlers returns handlers using the clients of a configuration
+func newTestHandlers(t *testing.T, cfg *config.Config) *Handlers {
+	t.Helper()
+	clients, err := ucloud.NewClientSet(cfg)
+	if err != nil {
+		t.Fatalf("NewClientSet: %v", err)
+	}
+
+	h := NewHandlers(clients, nil)
+	h.quotas = newQuotaTracker(config.QuotaConfig{})
+	return h
+}
+
+// dryRunCase is a mutating tool call and the actions its dry run should plan
+type dryRunCase struct {
+	tool    string
+	handler func(*Handlers, context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	args    map[string]interface{}
+
+	// responses override the shared responses of the fake API for this call
+	responses map[string]string
+
+	want []string
+}
+
+// testDryRuns calls each tool with dry_run set against a fake API serving
+// responses, and checks that the dry run succeeds, plans the expected actions
+// and sends none of them
+func testDryRuns(t *testing.T, responses map[string]string, cases []dryRunCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.tool, func(t *testing.T) {
+			api := newFakeAPI(responses, tc.responses)
+			h := newTestHandlers(t, &config.Config{Profile: testProfile(t, api)})
+			checkDryRun(t, h, api, tc)
+		})
+	}
+}
+
+// newFakeAPI returns a fake API serving responses, with overrides taking precedence
+func newFakeAPI(responses, overrides map[string]string) *fakeAPI {
+	api := &fakeAPI{responses: make(map[string]string)}
+	for action, body := range responses {
+		api.responses[action] = body
+	}
+	for action, body := range overrides {
+		api.responses[action] = body
+	}
+	return api
+}
+
+// checkDryRun calls a tool with dry_run set and checks that the dry run succeeds,
+// plans the expected actions and sends none of them to the API
+func checkDryRun(t *testing.T, h *Handlers, api *fakeAPI, tc dryRunCase) {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = tc.tool
+	request.Params.Arguments = map[string]interface{}{"dry_run": true}
+	for name, value := range tc.args {
+		request.Params.Arguments[name] = value
+	}
+
+	result, err := tc.handler(h, context.Background(), request)
+	if err != nil {
+		t.Fatalf("%s: %v", tc.tool, err)
+	}
+	if result.IsError {
+		t.Fatalf("%s: %s", tc.tool, resultText(result))
+	}
+
+	var dryRun struct {
+		DryRun   bool                    `json:"dry_run"`
+		Requests []ucloud.PlannedRequest `json:"requests"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &dryRun); err != nil {
+		t.Fatalf("%s: result is not a dry run: %v", tc.tool, err)
+	}
+	var planned []string
+	for _, req := range dryRun.Requests {
+		planned = append(planned, req.Action)
+	}
+	if !dryRun.DryRun || !reflect.DeepEqual(planned, tc.want) {
+		t.Errorf("%s planned %v, want %v", tc.tool, planned, tc.want)
+	}
+
+	for _, action := range api.sent() {
+		for _, want := range tc.want {
+			if action == want {
+				t.Errorf("%s sent %s to the API in a dry run", tc.tool, action)
+			}
+		}
+	}
+}
+
+func TestDryRunChange(t *testing.T) {
+	profile := testProfile(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("dry run sent %s to the API", requestParams(r).Get("Action"))
+	}))
+	client, err := ucloud.NewUCloudClient(&profile, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := func(client *ucloud.UCloudClient) error {
+		req := client.UHostClient.NewStopUHostInstanceRequest()
+		hostID := "uhost-test"
+		req.UHostId = &hostID
+		_, err := client.UHostClient.StopUHostInstance(req)
+		return err
+	}
+
+	tests := []struct {
+		name    string
+		run     func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error)
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "records requests",
+			run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+				if err := stop(client); err != nil {
+					return nil, err
+				}
+				return mcp.NewToolResultText("stopped"), nil
+			},
+			want: []string{"StopUHostInstance"},
+		},
+		{
+			name: "error",
+			run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+				return nil, errors.New("boom")
+			},
+			wantErr: "Dry run failed: boom",
+		},
+		{
+			name: "error result",
+			run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultError("instance is busy"), nil
+			},
+			wantErr: "Dry run failed: instance is busy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			request.Params.Name = "stop_instance"
+
+			result, err := dryRunChange(request, change{Client: client, Run: tt.run})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != "" {
+				if !result.IsError || resultText(result) != tt.wantErr {
+					t.Errorf("result = %q, want error %q", resultText(result), tt.wantErr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("result = %q, want success", resultText(result))
+			}
+
+			var dryRun struct {
+				Requests []ucloud.PlannedRequest `json:"requests"`
+			}
+			if err := json.Unmarshal([]byte(resultText(result)), &dryRun); err != nil {
+				t.Fatal(err)
+			}
+			var planned []string
+			for _, req := range dryRun.Requests {
+				planned = append(planned, req.Action)
+			}
+			if !reflect.DeepEqual(planned, tt.want) {
+				t.Errorf("planned %v, want %v", planned, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,405 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+	"github.com/ucloud/ucloud-sdk-go/services/unet"
+)
+
+// registerEIPTools registers the elastic IP tools
+func (s *MCPServer) registerEIPTools() {
+	listEIPsTool := mcp.NewTool("list_eips",
+		mcp.WithDescription("List UCloud elastic IPs (EIP) and the resources they are bound to"),
+		withProfile(),
+		withProjectID(),
+		withAllProjects(),
+	)
+	s.addTool(listEIPsTool, s.handlers.ListEIPsToolHandler, readOnlyTool)
+
+	findByIPTool := mcp.NewTool("find_instance_by_ip",
+		mcp.WithDescription("Find the UCloud instance a public or private IP address belongs to"),
+		mcp.WithString("ip",
+			mcp.Required(),
+			mcp.Description("IP address to look up, e.g. 106.75.1.2"),
+		),
+		withProfile(),
+		withProjectID(),
+		withAllProjects(),
+	)
+	s.addTool(findByIPTool, s.handlers.FindInstanceByIPToolHandler, readOnlyTool)
+
+	allocateEIPTool := mcp.NewTool("allocate_eip",
+		mcp.WithDescription("Allocate a new elastic IP"),
+		mcp.WithNumber("bandwidth",
+			mcp.Required(),
+			mcp.Description("Bandwidth of the EIP in Mbps"),
+			mcp.Min(1),
+		),
+		mcp.WithString("operator_name",
+			mcp.Description("Line of the EIP: Bgp, International or BGPPro"),
+			mcp.DefaultString("Bgp"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the EIP"),
+		),
+		mcp.WithString("pay_mode",
+			mcp.Description("Billing mode: Bandwidth, Traffic or ShareBandwidth (defaults to Bandwidth)"),
+		),
+		mcp.WithString("charge_type",
+			mcp.Description("Payment period: Month, Year or Dynamic (defaults to Month)"),
+		),
+		mcp.WithString("tag",
+			mcp.Description("Business group tag of the EIP"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(allocateEIPTool, s.handlers.AllocateEIPToolHandler, mutatingTool)
+
+	bindEIPTool := mcp.NewTool("bind_eip",
+		mcp.WithDescription("Bind an elastic IP to an instance"),
+		mcp.WithString("eip_id",
+			mcp.Required(),
+			mcp.Description("ID of the EIP to bind"),
+		),
+		mcp.WithString("instance_id",
+			mcp.Required(),
+			mcp.Description("ID of the instance to bind the EIP to"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(bindEIPTool, s.handlers.BindEIPToolHandler, mutatingTool)
+
+	unbindEIPTool := mcp.NewTool("unbind_eip",
+		mcp.WithDescription("Unbind an elastic IP from the resource it is bound to"),
+		mcp.WithString("eip_id",
+			mcp.Required(),
+			mcp.Description("ID of the EIP to unbind"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(unbindEIPTool, s.handlers.UnbindEIPToolHandler, mutatingTool)
+
+	releaseEIPTool := mcp.NewTool("release_eip",
+		mcp.WithDescription("Release an unbound elastic IP"),
+		mcp.WithString("eip_id",
+			mcp.Required(),
+			mcp.Description("ID of the EIP to release"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(releaseEIPTool, s.handlers.ReleaseEIPToolHandler, mutatingTool)
+
+	modifyBandwidthTool := mcp.NewTool("modify_eip_bandwidth",
+		mcp.WithDescription("Change the bandwidth of an elastic IP"),
+		mcp.WithString("eip_id",
+			mcp.Required(),
+			mcp.Description("ID of the EIP to change"),
+		),
+		mcp.WithNumber("bandwidth",
+			mcp.Required(),
+			mcp.Description("New bandwidth of the EIP in Mbps"),
+			mcp.Min(1),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(modifyBandwidthTool, s.handlers.ModifyEIPBandwidthToolHandler, mutatingTool)
+}
+
+// ListEIPsToolHandler handles EIP list tool requests
+func (h *Handlers) ListEIPsToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	clients, err := h.clientsForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var allEIPs []interface{}
+	for _, client := range clients {
+		eips, err := client.ListEIPs()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list EIPs in project %s: %v", client.ProjectID(), err)), nil
+		}
+
+		for _, eip := range eips {
+			if !isTagVisible(ctx, client, eip.Tag) {
+				continue
+			}
+			eipCopy := eip // Create a copy to avoid using loop variable reference
+			info := ucloud.FormatEIPInfo(&eipCopy)
+			info.ProjectID = client.ProjectID()
+			allEIPs = append(allEIPs, info)
+		}
+	}
+
+	log.Printf("Total EIPs found: %d", len(allEIPs))
+
+	jsonData, err := json.MarshalIndent(allEIPs, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal EIP data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// FindInstanceByIPToolHandler looks up the instance an IP address belongs to,
+// either through the EIP bound to it or through the instance's own addresses
+func (h *Handlers) FindInstanceByIPToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ip := getStringArg(request, "ip")
+	if net.ParseIP(ip) == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("%q is not an IP address", ip)), nil
+	}
+
+	clients, err := h.clientsForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	for _, client := range clients {
+		response := map[string]interface{}{
+			"ip":         ip,
+			"project_id": client.ProjectID(),
+		}
+
+		eip, found, err := client.FindEIPByIP(ip)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if found && (eip == nil || !isTagVisible(ctx, client, eip.Tag)) {
+			// The address belongs to a hidden EIP; don't reveal its instance either
+			continue
+		}
+		if eip != nil {
+			response["eip"] = ucloud.FormatEIPInfo(eip)
+			if eip.Resource.ResourceType != ucloud.ResourceTypeUHost {
+				// Bound to another kind of resource, or not bound at all
+				return jsonResult(response)
+			}
+			instance, err := client.DescribeInstance(eip.Resource.ResourceID)
+			if err == nil && isInstanceVisible(ctx, client, instance) {
+				info := ucloud.FormatInstanceInfo(instance)
+				info.ProjectID = client.ProjectID()
+				response["instance"] = info
+			}
+			return jsonResult(response)
+		}
+
+		instance, err := client.FindInstanceByIP(ip)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if instance != nil && isInstanceVisible(ctx, client, instance) {
+			info := ucloud.FormatInstanceInfo(instance)
+			info.ProjectID = client.ProjectID()
+			response["instance"] = info
+			return jsonResult(response)
+		}
+	}
+
+	return mcp.NewToolResultError(fmt.Sprintf("No EIP or instance found with IP %s", ip)), nil
+}
+
+// AllocateEIPToolHandler handles EIP allocation requests
+func (h *Handlers) AllocateEIPToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	spec := ucloud.EIPSpec{
+		Name:         getStringArg(request, "name"),
+		Bandwidth:    getIntArg(request, "bandwidth"),
+		OperatorName: getStringArg(request, "operator_name"),
+		PayMode:      getStringArg(request, "pay_mode"),
+		ChargeType:   getStringArg(request, "charge_type"),
+		Tag:          getStringArg(request, "tag"),
+	}
+	if spec.Bandwidth <= 0 {
+		return mcp.NewToolResultError("bandwidth must be a positive number of Mbps"), nil
+	}
+	if spec.OperatorName == "" {
+		spec.OperatorName = "Bgp"
+	}
+
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if !grantFromContext(ctx).allowsTag(client.Region(), client.ProjectID(), spec.Tag) {
+		return mcp.NewToolResultError(fmt.Sprintf("permission denied: tag %q is outside the tags allowed for %s",
+			spec.Tag, IdentityFromContext(ctx))), nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: []target{{ID: spec.Name, Tag: spec.Tag}},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			eipID, ip, err := client.AllocateEIP(spec)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(map[string]interface{}{
+				"eip_id":    eipID,
+				"ip":        ip,
+				"bandwidth": spec.Bandwidth,
+			})
+		},
+	})
+}
+
+// BindEIPToolHandler handles EIP bind requests
+func (h *Handlers) BindEIPToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	instanceID := getStringArg(request, "instance_id")
+
+	client, eip, result := h.eipForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+	if eip.Status != ucloud.EIPStatusFree {
+		return mcp.NewToolResultError(fmt.Sprintf("EIP %s is already bound to %s", eip.EIPId,
+			eip.Resource.ResourceID)), nil
+	}
+
+	instance, err := client.DescribeInstance(instanceID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to describe instance %v: %v", instanceID, err)), nil
+	}
+	if err := checkInstanceTag(ctx, client, instance); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client: client,
+		Targets: []target{
+			{ID: eip.EIPId, Tag: eip.Tag},
+			{ID: instance.UHostId, Tag: instance.Tag},
+		},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			if err := client.BindEIP(eip.EIPId, instance.UHostId); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(map[string]interface{}{
+				"eip_id":      eip.EIPId,
+				"instance_id": instance.UHostId,
+				"bound":       true,
+			})
+		},
+	})
+}
+
+// UnbindEIPToolHandler handles EIP unbind requests
+func (h *Handlers) UnbindEIPToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, eip, result := h.eipForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+	if eip.Status != ucloud.EIPStatusUsed {
+		return mcp.NewToolResultError(fmt.Sprintf("EIP %s is %s, not bound", eip.EIPId, eip.Status)), nil
+	}
+
+	targets := []target{{ID: eip.EIPId, Tag: eip.Tag}}
+	if eip.Resource.ResourceType == ucloud.ResourceTypeUHost {
+		instance, err := client.DescribeInstance(eip.Resource.ResourceID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to describe instance %v: %v", eip.Resource.ResourceID, err)), nil
+		}
+		if err := checkInstanceTag(ctx, client, instance); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		targets = append(targets, target{ID: instance.UHostId, Tag: instance.Tag})
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: targets,
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			if err := client.UnbindEIP(eip); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(map[string]interface{}{
+				"eip_id":      eip.EIPId,
+				"resource_id": eip.Resource.ResourceID,
+				"unbound":     true,
+			})
+		},
+	})
+}
+
+// ReleaseEIPToolHandler handles EIP release requests
+func (h *Handlers) ReleaseEIPToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, eip, result := h.eipForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+	if eip.Status != ucloud.EIPStatusFree {
+		return mcp.NewToolResultError(fmt.Sprintf("EIP %s is %s; unbind it before releasing", eip.EIPId,
+			eip.Status)), nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: []target{{ID: eip.EIPId, Tag: eip.Tag}},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			if err := client.ReleaseEIP(eip.EIPId); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(map[string]interface{}{
+				"eip_id":   eip.EIPId,
+				"released": true,
+			})
+		},
+	})
+}
+
+// ModifyEIPBandwidthToolHandler handles EIP bandwidth change requests
+func (h *Handlers) ModifyEIPBandwidthToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	bandwidth := getIntArg(request, "bandwidth")
+	if bandwidth <= 0 {
+		return mcp.NewToolResultError("bandwidth must be a positive number of Mbps"), nil
+	}
+
+	client, eip, result := h.eipForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: []target{{ID: eip.EIPId, Tag: eip.Tag}},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			if err := client.ModifyEIPBandwidth(eip.EIPId, bandwidth); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(map[string]interface{}{
+				"eip_id":             eip.EIPId,
+				"previous_bandwidth": eip.Bandwidth,
+				"bandwidth":          bandwidth,
+			})
+		},
+	})
+}
+
+// eipForRequest returns the client and the EIP named by the eip_id argument,
+// or an error result if the EIP cannot be found or the caller may not see it
+func (h *Handlers) eipForRequest(ctx context.Context, request mcp.CallToolRequest) (*ucloud.UCloudClient, *unet.UnetEIPSet, *mcp.CallToolResult) {
+	eipID := getStringArg(request, "eip_id")
+
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(err.Error())
+	}
+
+	eip, err := client.DescribeEIP(eipID)
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(fmt.Sprintf("Failed to describe EIP %v: %v", eipID, err))
+	}
+	if !isTagVisible(ctx, client, eip.Tag) {
+		return nil, nil, mcp.NewToolResultError(fmt.Sprintf("permission denied: EIP %s is outside the tags allowed for %s",
+			eip.EIPId, IdentityFromContext(ctx)))
+	}
+	return client, eip, nil
+}
@@ -0,0 +1,52 @@
+package mcp
+
+import "testing"
+
+const (
+	testEIP      = `{"EIPId":"eip-test","Name":"web","Bandwidth":2,"Status":"free","Tag":"Default","EIPAddr":[{"IP":"203.0.113.10","OperatorName":"Bgp"}]}`
+	testBoundEIP = `{"EIPId":"eip-test","Name":"web","Bandwidth":2,"Status":"used","Tag":"Default","EIPAddr":[{"IP":"203.0.113.10","OperatorName":"Bgp"}],"Resource":{"ResourceType":"uhost","ResourceID":"uhost-test"}}`
+)
+
+func TestEIPToolsDryRun(t *testing.T) {
+	responses := map[string]string{
+		"DescribeEIP":           `{"RetCode":0,"TotalCount":1,"EIPSet":[` + testEIP + `]}`,
+		"DescribeUHostInstance": `{"RetCode":0,"TotalCount":1,"UHostSet":[` + testInstance + `]}`,
+	}
+	bound := map[string]string{
+		"DescribeEIP": `{"RetCode":0,"TotalCount":1,"EIPSet":[` + testBoundEIP + `]}`,
+	}
+
+	testDryRuns(t, responses, []dryRunCase{
+		{
+			tool:    "allocate_eip",
+			handler: (*Handlers).AllocateEIPToolHandler,
+			args:    map[string]interface{}{"name": "web", "bandwidth": float64(2)},
+			want:    []string{"AllocateEIP"},
+		},
+		{
+			tool:    "bind_eip",
+			handler: (*Handlers).BindEIPToolHandler,
+			args:    map[string]interface{}{"eip_id": "eip-test", "instance_id": "uhost-test"},
+			want:    []string{"BindEIP"},
+		},
+		{
+			tool:      "unbind_eip",
+			handler:   (*Handlers).UnbindEIPToolHandler,
+			args:      map[string]interface{}{"eip_id": "eip-test"},
+			responses: bound,
+			want:      []string{"UnBindEIP"},
+		},
+		{
+			tool:    "release_eip",
+			handler: (*Handlers).ReleaseEIPToolHandler,
+			args:    map[string]interface{}{"eip_id": "eip-test"},
+			want:    []string{"ReleaseEIP"},
+		},
+		{
+			tool:    "modify_eip_bandwidth",
+			handler: (*Handlers).ModifyEIPBandwidthToolHandler,
+			args:    map[string]interface{}{"eip_id": "eip-test", "bandwidth": float64(5)},
+			want:    []string{"ModifyEIPBandwidth"},
+		},
+	})
+}
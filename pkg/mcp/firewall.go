@@ -0,0 +1,272 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+	"github.com/ucloud/ucloud-sdk-go/services/unet"
+)
+
+// registerFirewallTools registers the firewall tools
+func (s *MCPServer) registerFirewallTools() {
+	listFirewallsTool := mcp.NewTool("list_firewalls",
+		mcp.WithDescription("List UCloud firewalls with their rules"),
+		withProfile(),
+		withProjectID(),
+		withAllProjects(),
+	)
+	s.addTool(listFirewallsTool, s.handlers.ListFirewallsToolHandler, readOnlyTool)
+
+	describeFirewallTool := mcp.NewTool("describe_firewall",
+		mcp.WithDescription("Get the rules of a UCloud firewall and the resources it is applied to"),
+		mcp.WithString("firewall_id",
+			mcp.Required(),
+			mcp.Description("ID of the firewall to describe"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(describeFirewallTool, s.handlers.DescribeFirewallToolHandler, readOnlyTool)
+
+	updateRulesTool := mcp.NewTool("update_firewall_rules",
+		mcp.WithDescription("Replace all rules of a firewall. Use dry_run to preview the diff against the current rules."),
+		mcp.WithString("firewall_id",
+			mcp.Required(),
+			mcp.Description("ID of the firewall to update"),
+		),
+		mcp.WithString("rules",
+			mcp.Required(),
+			mcp.Description(`JSON array of the complete new rule set, e.g. [{"protocol":"TCP","port":"22",`+
+				`"source":"10.0.0.0/8","action":"ACCEPT","priority":"HIGH","remark":"ssh"}]`),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(updateRulesTool, s.handlers.UpdateFirewallRulesToolHandler, mutatingTool)
+
+	grantFirewallTool := mcp.NewTool("grant_firewall",
+		mcp.WithDescription("Apply a firewall to an instance, replacing the firewall it uses now"),
+		mcp.WithString("firewall_id",
+			mcp.Required(),
+			mcp.Description("ID of the firewall to apply"),
+		),
+		mcp.WithString("instance_id",
+			mcp.Required(),
+			mcp.Description("ID of the instance to apply the firewall to"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(grantFirewallTool, s.handlers.GrantFirewallToolHandler, mutatingTool)
+}
+
+// ListFirewallsToolHandler handles firewall list tool requests
+func (h *Handlers) ListFirewallsToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	clients, err := h.clientsForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var allFirewalls []interface{}
+	for _, client := range clients {
+		firewalls, err := client.ListFirewalls()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list firewalls in project %s: %v", client.ProjectID(), err)), nil
+		}
+
+		for _, firewall := range firewalls {
+			if !isTagVisible(ctx, client, firewall.Tag) {
+				continue
+			}
+			firewallCopy := firewall // Create a copy to avoid using loop variable reference
+			info := ucloud.FormatFirewallInfo(&firewallCopy)
+			info.ProjectID = client.ProjectID()
+			allFirewalls = append(allFirewalls, info)
+		}
+	}
+
+	log.Printf("Total firewalls found: %d", len(allFirewalls))
+
+	jsonData, err := json.MarshalIndent(allFirewalls, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal firewall data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// DescribeFirewallToolHandler handles firewall description requests
+func (h *Handlers) DescribeFirewallToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, firewall, result := h.firewallForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	resources, err := client.FirewallResources(firewall.FWId)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	type resourceInfo struct {
+		ID        string `json:"id"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		PrivateIP string `json:"private_ip,omitempty"`
+	}
+	info := ucloud.FormatFirewallInfo(firewall)
+	info.ProjectID = client.ProjectID()
+	attached := make([]resourceInfo, 0, len(resources))
+	for _, resource := range resources {
+		if !grantFromContext(ctx).allowsTag(client.Region(), client.ProjectID(), resource.Tag) ||
+			!client.ScopeContains(resource.ResourceID, resource.Tag) {
+			continue
+		}
+		attached = append(attached, resourceInfo{
+			ID:        resource.ResourceID,
+			Type:      resource.ResourceType,
+			Name:      resource.Name,
+			PrivateIP: resource.PrivateIP,
+		})
+	}
+
+	response := struct {
+		*ucloud.FirewallInfo
+		Resources []resourceInfo `json:"resources"`
+	}{info, attached}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal firewall info: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// UpdateFirewallRulesToolHandler handles firewall rule updates
+func (h *Handlers) UpdateFirewallRulesToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var rules []ucloud.FirewallRule
+	if err := getJSONArg(request, "rules", &rules); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if len(rules) == 0 {
+		return mcp.NewToolResultError("rules must contain at least one rule"), nil
+	}
+	for i, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("rules[%d]: %v", i, err)), nil
+		}
+	}
+
+	client, firewall, result := h.firewallForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+	diff := ucloud.DiffFirewallRules(ucloud.FirewallRules(firewall), rules)
+
+	// New rules apply to every resource the firewall is attached to
+	resources, err := client.FirewallResources(firewall.FWId)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	targets := []target{{ID: firewall.FWId, Tag: firewall.Tag}}
+	hidden := 0
+	for _, resource := range resources {
+		if !isTagVisible(ctx, client, resource.Tag) || !client.ScopeContains(resource.ResourceID, resource.Tag) {
+			hidden++
+			continue
+		}
+		targets = append(targets, target{ID: resource.ResourceID, Tag: resource.Tag})
+	}
+	if hidden > 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("permission denied: firewall %s is also applied to %d resource(s) outside the tags or scope allowed for %s",
+			firewall.FWId, hidden, IdentityFromContext(ctx))), nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: targets,
+		Preview: diff,
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			if err := client.UpdateFirewallRules(firewall.FWId, rules); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(map[string]interface{}{
+				"firewall_id": firewall.FWId,
+				"diff":        diff,
+			})
+		},
+	})
+}
+
+// GrantFirewallToolHandler handles requests to apply a firewall to an instance
+func (h *Handlers) GrantFirewallToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	instanceID := getStringArg(request, "instance_id")
+
+	client, firewall, result := h.firewallForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	instance, err := client.DescribeInstance(instanceID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to describe instance %v: %v", instanceID, err)), nil
+	}
+	if err := checkInstanceTag(ctx, client, instance); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	previous, err := client.InstanceFirewall(instance.UHostId)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	response := map[string]interface{}{
+		"firewall_id": firewall.FWId,
+		"instance_id": instance.UHostId,
+	}
+	if previous != nil {
+		if previous.FWId == firewall.FWId {
+			return mcp.NewToolResultError(fmt.Sprintf("Firewall %s is already applied to %s", firewall.FWId,
+				instance.UHostId)), nil
+		}
+		response["previous_firewall_id"] = previous.FWId
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client: client,
+		Targets: []target{
+			{ID: firewall.FWId, Tag: firewall.Tag},
+			{ID: instance.UHostId, Tag: instance.Tag},
+		},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			if err := client.GrantFirewall(firewall.FWId, instance.UHostId); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(response)
+		},
+	})
+}
+
+// firewallForRequest returns the client and the firewall named by the firewall_id argument,
+// or an error result if the firewall cannot be found or the caller may not see it
+func (h *Handlers) firewallForRequest(ctx context.Context, request mcp.CallToolRequest) (*ucloud.UCloudClient, *unet.FirewallDataSet, *mcp.CallToolResult) {
+	firewallID := getStringArg(request, "firewall_id")
+
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(err.Error())
+	}
+
+	firewall, err := client.DescribeFirewall(firewallID)
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(fmt.Sprintf("Failed to describe firewall %v: %v", firewallID, err))
+	}
+	if !isTagVisible(ctx, client, firewall.Tag) {
+		return nil, nil, mcp.NewToolResultError(fmt.Sprintf("permission denied: firewall %s is outside the tags allowed for %s",
+			firewall.FWId, IdentityFromContext(ctx)))
+	}
+	return client, firewall, nil
+}
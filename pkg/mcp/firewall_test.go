@@ -0,0 +1,32 @@
+package mcp
+
+import "testing"
+
+const testFirewall = `{"FWId":"firewall-test","Name":"web","Tag":"Default","Rule":[{"ProtocolType":"TCP","DstPort":"22","SrcIP":"0.0.0.0/0","RuleAction":"ACCEPT","Priority":"HIGH"}]}`
+
+func TestFirewallToolsDryRun(t *testing.T) {
+	responses := map[string]string{
+		"DescribeFirewall":                    `{"RetCode":0,"DataSet":[` + testFirewall + `]}`,
+		"DescribeFirewall?ResourceType=uhost": `{"RetCode":0,"DataSet":[]}`,
+		"DescribeFirewallResource":            `{"RetCode":0,"TotalCount":1,"ResourceSet":[{"ResourceID":"uhost-test","ResourceType":"uhost","Name":"web","Tag":"Default"}]}`,
+		"DescribeUHostInstance":               `{"RetCode":0,"TotalCount":1,"UHostSet":[` + testInstance + `]}`,
+	}
+
+	testDryRuns(t, responses, []dryRunCase{
+		{
+			tool:    "update_firewall_rules",
+			handler: (*Handlers).UpdateFirewallRulesToolHandler,
+			args: map[string]interface{}{
+				"firewall_id": "firewall-test",
+				"rules":       `[{"protocol":"TCP","port":"443","source":"0.0.0.0/0","action":"ACCEPT","priority":"HIGH"}]`,
+			},
+			want: []string{"UpdateFirewall"},
+		},
+		{
+			tool:    "grant_firewall",
+			handler: (*Handlers).GrantFirewallToolHandler,
+			args:    map[string]interface{}{"firewall_id": "firewall-test", "instance_id": "uhost-test"},
+			want:    []string{"GrantFirewall"},
+		},
+	})
+}
@@ -5,35 +5,67 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
 	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
 	"github.com/ucloud/ucloud-mcp-server/pkg/utils"
+	"github.com/ucloud/ucloud-sdk-go/services/uhost"
 )
 
 // Handlers contains MCP handlers
 type Handlers struct {
-	ucloudClient *ucloud.UCloudClient
+	clients   atomic.Pointer[ucloud.ClientSet]
+	rbac      *rbac
+	approvals *approvalQueue
+	quotas    *quotaTracker
+	dryRun    bool
 }
 
-// NewHandlers creates new MCP handlers
-func NewHandlers(ucloudClient *ucloud.UCloudClient) *Handlers {
-	return &Handlers{
-		ucloudClient: ucloudClient,
-	}
+// NewHandlers creates new MCP handlers enforcing the given roles
+func NewHandlers(clients *ucloud.ClientSet, roles map[string]config.RoleConfig) *Handlers {
+	h := &Handlers{rbac: &rbac{roles: roles}}
+	h.clients.Store(clients)
+	return h
+}
+
+// SetClients atomically replaces the UCloud clients used by subsequent calls
+func (h *Handlers) SetClients(clients *ucloud.ClientSet) {
+	h.clients.Store(clients)
 }
 
 // DescribeInstanceHandler handles instance description requests
 func (h *Handlers) DescribeInstanceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	instanceID := request.Params.Arguments["instance_id"].(string)
 
-	instance, err := h.ucloudClient.DescribeInstance(instanceID)
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	instance, err := client.DescribeInstance(instanceID)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to describe instance %v: %v", instanceID, err)), nil
 	}
+	if err := checkInstanceTag(ctx, client, instance); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	info := ucloud.FormatInstanceInfo(instance)
+	info.ProjectID = client.ProjectID()
+	if getBoolArg(request, "include_firewall") {
+		firewall, err := client.InstanceFirewall(instance.UHostId)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		// The firewall is in scope through the instance, but its tag must be visible too
+		if firewall != nil && isTagVisible(ctx, client, firewall.Tag) {
+			info.Firewall = ucloud.FormatFirewallInfo(firewall)
+		}
+	}
+
 	jsonData, err := json.MarshalIndent(info, "", "  ")
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal instance info: %v", err)), nil
@@ -46,12 +78,20 @@ func (h *Handlers) DescribeInstanceHandler(ctx context.Context, request mcp.Call
 func (h *Handlers) GetInstanceMetricsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	instanceID := request.Params.Arguments["instance_id"].(string)
 
-	instance, err := h.ucloudClient.DescribeInstance(instanceID)
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	instance, err := client.DescribeInstance(instanceID)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get instance %v: %v", instanceID, err)), nil
 	}
+	if err := checkInstanceTag(ctx, client, instance); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	metrics, err := h.ucloudClient.GetInstanceMetrics(instance)
+	metrics, err := client.GetInstanceMetrics(instance)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get metrics: %v", err)), nil
 	}
@@ -63,6 +103,7 @@ func (h *Handlers) GetInstanceMetricsHandler(ctx context.Context, request mcp.Ca
 	// Build metrics response
 	metricsResponse := map[string]interface{}{
 		"instance_id": instanceID,
+		"project_id":  client.ProjectID(),
 		"name":        instance.Name,
 		"status":      instance.State,
 		"basic_info": map[string]interface{}{
@@ -86,6 +127,7 @@ func (h *Handlers) GetInstanceMetricsHandler(ctx context.Context, request mcp.Ca
 
 // InstanceStatusHandler handles instance status retrieval requests
 func (h *Handlers) InstanceStatusHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ctx = h.withGrant(ctx)
 	instanceStatusURI := "uhost://instances/{instance_id}/status"
 	variables, err := utils.ParsePath(instanceStatusURI, request.Params.URI)
 	if err != nil {
@@ -97,10 +139,18 @@ func (h *Handlers) InstanceStatusHandler(ctx context.Context, request mcp.ReadRe
 		return nil, fmt.Errorf("instance_id not found in path")
 	}
 
-	instance, err := h.ucloudClient.DescribeInstance(instanceID)
+	client, err := h.resourceClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := client.DescribeInstance(instanceID)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkInstanceTag(ctx, client, instance); err != nil {
+		return nil, err
+	}
 
 	status := map[string]string{
 		"status": instance.State,
@@ -122,9 +172,15 @@ func (h *Handlers) InstanceStatusHandler(ctx context.Context, request mcp.ReadRe
 
 // InstanceListHandler handles instance list retrieval requests
 func (h *Handlers) InstanceListHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ctx = h.withGrant(ctx)
 	log.Printf("Listing UCloud instances...")
 
-	instances, err := h.ucloudClient.ListInstances()
+	client, err := h.resourceClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := client.ListInstances()
 	if err != nil {
 		log.Printf("Error listing instances: %v", err)
 		return nil, fmt.Errorf("failed to list instances: %v", err)
@@ -132,6 +188,9 @@ func (h *Handlers) InstanceListHandler(ctx context.Context, request mcp.ReadReso
 
 	var allInstances []interface{}
 	for _, instance := range instances {
+		if !isInstanceVisible(ctx, client, &instance) {
+			continue
+		}
 		instanceCopy := instance // Create a copy to avoid using loop variable reference
 		info := ucloud.FormatInstanceInfo(&instanceCopy)
 		allInstances = append(allInstances, info)
@@ -160,24 +219,37 @@ func (h *Handlers) InstanceListHandler(ctx context.Context, request mcp.ReadReso
 func (h *Handlers) InstanceListToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	log.Printf("Listing all UCloud instances...")
 
-	instances, err := h.ucloudClient.ListInstances()
+	clients, err := h.clientsForRequest(ctx, request)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to list instances: %v", err)), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
 	var allInstancesWithMetrics []interface{}
-	for _, instance := range instances {
-		instanceCopy := instance // Create a copy to avoid using loop variable reference
-		log.Printf("Processing instance: %s (%s)", instanceCopy.Name, instanceCopy.UHostId)
+	for _, client := range clients {
+		instances, err := client.ListInstances()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list instances in project %s: %v", client.ProjectID(), err)), nil
+		}
 
-		// Get instance metrics
-		metrics, err := h.ucloudClient.GetInstanceMetrics(&instanceCopy)
+		var visible []*uhost.UHostInstanceSet
+		for i := range instances {
+			if isInstanceVisible(ctx, client, &instances[i]) {
+				visible = append(visible, &instances[i])
+			}
+		}
+
+		// Get the metrics of all instances at once
+		metrics, err := client.GetInstancesMetrics(visible)
 		if err != nil {
-			log.Printf("Warning: Failed to get metrics for instance %s: %v", instanceCopy.UHostId, err)
+			log.Printf("Warning: Failed to get metrics for instances in project %s: %v", client.ProjectID(), err)
 		}
 
-		info := ucloud.FormatInstanceInfoWithMetrics(&instanceCopy, metrics)
-		allInstancesWithMetrics = append(allInstancesWithMetrics, info)
+		for _, instance := range visible {
+			log.Printf("Processing instance: %s (%s)", instance.Name, instance.UHostId)
+			info := ucloud.FormatInstanceInfoWithMetrics(instance, metrics[instance.UHostId])
+			info.ProjectID = client.ProjectID()
+			allInstancesWithMetrics = append(allInstancesWithMetrics, info)
+		}
 	}
 
 	log.Printf("Total instances with metrics found: %d", len(allInstancesWithMetrics))
@@ -197,19 +269,30 @@ func (h *Handlers) InstanceStatusToolHandler(ctx context.Context, request mcp.Ca
 
 	// No parameters needed, return status for all instances
 
-	instances, err := h.ucloudClient.ListInstances()
+	clients, err := h.clientsForRequest(ctx, request)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to list instances: %v", err)), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
 	var statusList []map[string]string
-	for _, instance := range instances {
-		status := map[string]string{
-			"id":     instance.UHostId,
-			"name":   instance.Name,
-			"status": instance.State,
+	for _, client := range clients {
+		instances, err := client.ListInstances()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list instances in project %s: %v", client.ProjectID(), err)), nil
+		}
+
+		for _, instance := range instances {
+			if !isInstanceVisible(ctx, client, &instance) {
+				continue
+			}
+			status := map[string]string{
+				"id":         instance.UHostId,
+				"project_id": client.ProjectID(),
+				"name":       instance.Name,
+				"status":     instance.State,
+			}
+			statusList = append(statusList, status)
 		}
-		statusList = append(statusList, status)
 	}
 
 	jsonData, err := json.MarshalIndent(statusList, "", "  ")
@@ -219,3 +302,233 @@ func (h *Handlers) InstanceStatusToolHandler(ctx context.Context, request mcp.Ca
 
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
+
+// ListProjectsToolHandler handles project list tool requests
+func (h *Handlers) ListProjectsToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Printf("Listing UCloud projects...")
+
+	client, err := h.profileClientForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	projects, err := client.ListProjects()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list projects: %v", err)), nil
+	}
+
+	g := grantFromContext(ctx)
+	var projectList []map[string]interface{}
+	for _, project := range projects {
+		if !g.allowsScope(client.Region(), project.ProjectId) {
+			continue
+		}
+		projectList = append(projectList, map[string]interface{}{
+			"project_id":   project.ProjectId,
+			"name":         project.ProjectName,
+			"is_default":   project.ProjectId == client.ProjectID(),
+			"member_count": project.MemberCount,
+			"create_time":  time.Unix(int64(project.CreateTime), 0).Format(time.RFC3339),
+		})
+	}
+
+	jsonData, err := json.MarshalIndent(projectList, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal project data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// ListProfilesToolHandler handles credential profile list tool requests
+func (h *Handlers) ListProfilesToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	clients := h.clients.Load()
+
+	var profileList []map[string]interface{}
+	for _, name := range clients.Profiles() {
+		client, err := clients.Client(name)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		profileList = append(profileList, map[string]interface{}{
+			"profile":    name,
+			"is_default": name == clients.DefaultProfile(),
+			"region":     client.Region(),
+			"project_id": client.ProjectID(),
+			"base_url":   client.BaseURL(),
+		})
+	}
+
+	jsonData, err := json.MarshalIndent(profileList, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal profile data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// GetOperationStatusToolHandler reports the status of an operation parked for approval
+func (h *Handlers) GetOperationStatusToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	operationID := getStringArg(request, "operation_id")
+
+	op, ok := h.approvals.get(operationID)
+	if !ok || op.Identity != IdentityFromContext(ctx) {
+		return mcp.NewToolResultError(fmt.Sprintf("Operation %s not found", operationID)), nil
+	}
+
+	jsonData, err := json.MarshalIndent(op, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal operation: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// profileClientForRequest returns the client of the profile selected by the tool call
+func (h *Handlers) profileClientForRequest(ctx context.Context, request mcp.CallToolRequest) (*ucloud.UCloudClient, error) {
+	clients := h.clients.Load()
+
+	profile := getStringArg(request, "profile")
+	client, err := clients.Client(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile == "" {
+		profile = clients.DefaultProfile()
+	}
+	log.Printf("Tool %s using profile %s", request.Params.Name, profile)
+
+	return observeClient(ctx, client), nil
+}
+
+// clientForRequest returns a client bound to the profile and project selected by the tool call.
+// The caller must be allowed to access the project and region.
+func (h *Handlers) clientForRequest(ctx context.Context, request mcp.CallToolRequest) (*ucloud.UCloudClient, error) {
+	client, err := h.profileClientForRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	projectID := getStringArg(request, "project_id")
+	client, err = client.WithProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := grantFromContext(ctx).checkScope(client); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// clientsForRequest returns one client per project the tool call should cover.
+// Setting all_projects aggregates over every allowed project of the profile
+// that the caller may access.
+func (h *Handlers) clientsForRequest(ctx context.Context, request mcp.CallToolRequest) ([]*ucloud.UCloudClient, error) {
+	if !getBoolArg(request, "all_projects") {
+		client, err := h.clientForRequest(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		return []*ucloud.UCloudClient{client}, nil
+	}
+
+	profileClient, err := h.profileClientForRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	projectIDs, err := profileClient.AllowedProjectIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	g := grantFromContext(ctx)
+	var clients []*ucloud.UCloudClient
+	for _, projectID := range projectIDs {
+		if !g.allowsScope(profileClient.Region(), projectID) {
+			continue
+		}
+		client, err := profileClient.WithProject(projectID)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+	return clients, nil
+}
+
+// resourceClient returns the default client for a resource read, which must be
+// within the scope of the caller's roles
+func (h *Handlers) resourceClient(ctx context.Context) (*ucloud.UCloudClient, error) {
+	client, err := h.clients.Load().Default()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := grantFromContext(ctx).checkScope(client); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// isInstanceVisible reports whether the caller's roles allow it to see an instance
+func isInstanceVisible(ctx context.Context, client *ucloud.UCloudClient, instance *uhost.UHostInstanceSet) bool {
+	g := grantFromContext(ctx)
+	return g.allowsTag(client.Region(), client.ProjectID(), instance.Tag)
+}
+
+// isTagVisible reports whether the caller's roles allow it to see a resource with the given tag
+func isTagVisible(ctx context.Context, client *ucloud.UCloudClient, tag string) bool {
+	g := grantFromContext(ctx)
+	return g.allowsTag(client.Region(), client.ProjectID(), tag)
+}
+
+// checkInstanceTag returns a permission error unless the caller may see an instance
+func checkInstanceTag(ctx context.Context, client *ucloud.UCloudClient, instance *uhost.UHostInstanceSet) error {
+	if isInstanceVisible(ctx, client, instance) {
+		return nil
+	}
+	return fmt.Errorf("permission denied: instance %s is outside the tags allowed for %s",
+		instance.UHostId, IdentityFromContext(ctx))
+}
+
+// getStringArg returns an optional string argument, or an empty string if absent
+func getStringArg(request mcp.CallToolRequest, name string) string {
+	value, _ := request.Params.Arguments[name].(string)
+	return value
+}
+
+// getBoolArg returns an optional boolean argument, or false if absent
+func getBoolArg(request mcp.CallToolRequest, name string) bool {
+	value, _ := request.Params.Arguments[name].(bool)
+	return value
+}
+
+// getIntArg returns an optional numeric argument, or 0 if absent
+func getIntArg(request mcp.CallToolRequest, name string) int {
+	value, _ := request.Params.Arguments[name].(float64)
+	return int(value)
+}
+
+// getJSONArg decodes an argument given either as a JSON string or as a JSON value
+func getJSONArg(request mcp.CallToolRequest, name string, v interface{}) error {
+	value, ok := request.Params.Arguments[name]
+	if !ok {
+		return fmt.Errorf("%s is required", name)
+	}
+
+	data, isString := value.(string)
+	if !isString {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %v", name, err)
+		}
+		data = string(encoded)
+	}
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"log"
+	"path"
+
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+)
+
+// toolAccess tells whether a tool only reads UCloud resources or changes them
+type toolAccess int
+
+const (
+	readOnlyTool toolAccess = iota
+	mutatingTool
+)
+
+// policy decides which tools, resources and prompts are advertised to clients
+type policy struct {
+	cfg config.PolicyConfig
+}
+
+// allowsTool reports whether a tool may be registered
+func (p *policy) allowsTool(name string, access toolAccess) bool {
+	if p.cfg.ReadOnly && access == mutatingTool {
+		return false
+	}
+	return allowedByFilter(p.cfg.Tools, name)
+}
+
+// allowsResource reports whether a resource may be registered
+func (p *policy) allowsResource(name string) bool {
+	return allowedByFilter(p.cfg.Resources, name)
+}
+
+// allowsPrompt reports whether a prompt may be registered
+func (p *policy) allowsPrompt(name string) bool {
+	return allowedByFilter(p.cfg.Prompts, name)
+}
+
+// allowedByFilter applies the enabled and disabled patterns of a filter to a name
+func allowedByFilter(filter config.FilterConfig, name string) bool {
+	if len(filter.Enabled) > 0 && !matchesAny(filter.Enabled, name) {
+		return false
+	}
+	return !matchesAny(filter.Disabled, name)
+}
+
+// matchesAny reports whether a name matches any of the glob patterns
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			log.Printf("Ignoring invalid pattern %q: %v", pattern, err)
+			continue
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
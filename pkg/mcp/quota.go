@@ -0,0 +1,206 @@
+package mcp
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+)
+
+// Usage metrics, published at /debug/vars
+var (
+	toolCallsMetric     = expvar.NewMap("tool_calls")
+	quotaExceededMetric = expvar.NewMap("quota_exceeded")
+)
+
+// quotaSweepInterval is how often idle usage counters are dropped
+const quotaSweepInterval = time.Hour
+
+// quotaTracker counts tool usage per session and per caller in sliding windows
+type quotaTracker struct {
+	mu        sync.Mutex
+	cfg       config.QuotaConfig
+	events    map[string][]time.Time
+	lastSweep time.Time
+}
+
+// newQuotaTracker creates a tracker enforcing the configured quotas
+func newQuotaTracker(cfg config.QuotaConfig) *quotaTracker {
+	return &quotaTracker{
+		cfg:       cfg,
+		events:    make(map[string][]time.Time),
+		lastSweep: time.Now(),
+	}
+}
+
+// quotaSubject is a session or caller that quotas apply to
+type quotaSubject struct {
+	kind   string
+	id     string
+	limits config.QuotaLimits
+}
+
+// subjects returns the session and caller of a tool call
+func (q *quotaTracker) subjects(ctx context.Context) []quotaSubject {
+	subjects := []quotaSubject{{kind: "caller", id: IdentityFromContext(ctx), limits: q.cfg.Caller}}
+	if sessionID := SessionIDFromContext(ctx); sessionID != "" {
+		subjects = append(subjects, quotaSubject{kind: "session", id: sessionID, limits: q.cfg.Session})
+	}
+	return subjects
+}
+
+// quotaCheck limits the events of one counter. A zero limit allows everything.
+type quotaCheck struct {
+	key   string
+	limit int
+}
+
+// allow records an event for every check unless one of them already had limit
+// events within window. Then nothing is recorded and the index of the first
+// exhausted check is returned, so a refused call uses up no quota.
+func (q *quotaTracker) allow(checks []quotaCheck, window time.Duration) (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	q.sweep(now)
+
+	cutoff := now.Add(-window)
+	for i, check := range checks {
+		if check.limit <= 0 {
+			continue
+		}
+		events := q.events[check.key]
+		j := 0
+		for j < len(events) && events[j].Before(cutoff) {
+			j++
+		}
+		events = events[j:]
+		q.events[check.key] = events
+		if len(events) >= check.limit {
+			return i, false
+		}
+	}
+
+	for _, check := range checks {
+		if check.limit > 0 {
+			q.events[check.key] = append(q.events[check.key], now)
+		}
+	}
+	return -1, true
+}
+
+// sweep drops counters without events in the last sweep interval. The tracker must be locked.
+func (q *quotaTracker) sweep(now time.Time) {
+	if now.Sub(q.lastSweep) < quotaSweepInterval {
+		return
+	}
+	q.lastSweep = now
+	cutoff := now.Add(-quotaSweepInterval)
+	for key, events := range q.events {
+		if len(events) == 0 || events[len(events)-1].Before(cutoff) {
+			delete(q.events, key)
+		}
+	}
+}
+
+// checkCall enforces the per-minute call limits of a tool
+func (q *quotaTracker) checkCall(ctx context.Context, tool string) error {
+	subjects := q.subjects(ctx)
+	checks := make([]quotaCheck, len(subjects))
+	for i, subject := range subjects {
+		checks[i] = quotaCheck{
+			key:   fmt.Sprintf("%s/%s/calls/%s", subject.kind, subject.id, tool),
+			limit: callLimit(subject.limits, tool),
+		}
+	}
+
+	if i, ok := q.allow(checks, time.Minute); !ok {
+		subject := subjects[i]
+		quotaExceededMetric.Add(subject.kind+"/calls_per_minute/"+tool, 1)
+		return fmt.Errorf("quota exceeded: %s %s may call %s at most %d times per minute",
+			subject.kind, subject.id, tool, checks[i].limit)
+	}
+	return nil
+}
+
+// checkResources enforces the limit on resources affected by a single call
+func (q *quotaTracker) checkResources(tool string, count int) error {
+	if max := q.cfg.MaxResourcesPerCall; max > 0 && count > max {
+		quotaExceededMetric.Add("call/max_resources_per_call/"+tool, 1)
+		return fmt.Errorf("quota exceeded: %s would affect %d resources, at most %d are allowed per call",
+			tool, count, max)
+	}
+	return nil
+}
+
+// checkMutation enforces the hourly mutation limits
+func (q *quotaTracker) checkMutation(ctx context.Context, tool string) error {
+	subjects := q.subjects(ctx)
+	checks := make([]quotaCheck, len(subjects))
+	for i, subject := range subjects {
+		checks[i] = quotaCheck{
+			key:   fmt.Sprintf("%s/%s/mutations", subject.kind, subject.id),
+			limit: subject.limits.MutationsPerHour,
+		}
+	}
+
+	if i, ok := q.allow(checks, time.Hour); !ok {
+		subject := subjects[i]
+		quotaExceededMetric.Add(subject.kind+"/mutations_per_hour/"+tool, 1)
+		return fmt.Errorf("quota exceeded: %s %s may make at most %d changes per hour",
+			subject.kind, subject.id, checks[i].limit)
+	}
+	return nil
+}
+
+// callLimit returns the per-minute limit of a tool: an exact override, then the
+// most specific matching pattern, then the default
+func callLimit(limits config.QuotaLimits, tool string) int {
+	if limit, ok := limits.Tools[tool]; ok {
+		return limit
+	}
+
+	patterns := make([]string, 0, len(limits.Tools))
+	for pattern := range limits.Tools {
+		patterns = append(patterns, pattern)
+	}
+	// Longer patterns are more specific
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, tool); matched {
+			return limits.Tools[pattern]
+		}
+	}
+
+	return limits.CallsPerMinute
+}
+
+// limit wraps a tool handler with the per-minute call quotas and counts calls
+func (h *Handlers) limit(tool string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		toolCallsMetric.Add(tool, 1)
+
+		// Approved operations were counted when they were first called
+		if approvedOperation(ctx) == "" {
+			if err := h.quotas.checkCall(ctx, tool); err != nil {
+				log.Printf("Rejected call to %s: %v", tool, err)
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		return next(ctx, request)
+	}
+}
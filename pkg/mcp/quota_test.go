@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+)
+
+func TestQuotaTrackerAllow(t *testing.T) {
+	q := newQuotaTracker(config.QuotaConfig{})
+
+	for i := 0; i < 2; i++ {
+		if _, ok := q.allow([]quotaCheck{{key: "a", limit: 2}}, time.Minute); !ok {
+			t.Fatalf("event %d within the limit was refused", i+1)
+		}
+	}
+	if i, ok := q.allow([]quotaCheck{{key: "a", limit: 2}}, time.Minute); ok || i != 0 {
+		t.Errorf("allow over the limit = (%d, %v), want (0, false)", i, ok)
+	}
+	if _, ok := q.allow([]quotaCheck{{key: "b", limit: 2}}, time.Minute); !ok {
+		t.Errorf("counters of different keys must be independent")
+	}
+	if _, ok := q.allow([]quotaCheck{{key: "a", limit: 0}}, time.Minute); !ok {
+		t.Errorf("a zero limit must allow everything")
+	}
+
+	// Events outside the window no longer count
+	q.events["a"] = []time.Time{time.Now().Add(-2 * time.Minute), time.Now().Add(-90 * time.Second)}
+	if _, ok := q.allow([]quotaCheck{{key: "a", limit: 2}}, time.Minute); !ok {
+		t.Errorf("events outside the window must not count")
+	}
+	if got := len(q.events["a"]); got != 1 {
+		t.Errorf("expired events were kept: %d events, want 1", got)
+	}
+}
+
+func TestQuotaTrackerRefusedCallUsesNoQuota(t *testing.T) {
+	q := newQuotaTracker(config.QuotaConfig{
+		Caller:  config.QuotaLimits{MutationsPerHour: 5},
+		Session: config.QuotaLimits{MutationsPerHour: 1},
+	})
+	ctx := context.WithValue(context.Background(), identityKey{}, "alice")
+	ctx = context.WithValue(ctx, sessionKey{}, "session-1")
+
+	if err := q.checkMutation(ctx, "reboot_instance"); err != nil {
+		t.Fatalf("first mutation: %v", err)
+	}
+	if err := q.checkMutation(ctx, "reboot_instance"); err == nil {
+		t.Fatalf("second mutation of the session should exceed its quota")
+	}
+
+	if got := len(q.events["caller/alice/mutations"]); got != 1 {
+		t.Errorf("caller has %d recorded mutations, want 1: the refused call must not count", got)
+	}
+}
+
+func TestCallLimit(t *testing.T) {
+	limits := config.QuotaLimits{
+		CallsPerMinute: 60,
+		Tools: map[string]int{
+			"reboot_instance":   2,
+			"*_instance":        10,
+			"reboot_*":          5,
+			"describe_instance": 0,
+		},
+	}
+
+	tests := []struct {
+		tool string
+		want int
+	}{
+		{"reboot_instance", 2},
+		{"describe_instance", 0},
+		{"start_instance", 10},
+		{"reboot_database", 5},
+		{"list_instances", 60},
+	}
+
+	for _, tt := range tests {
+		if got := callLimit(limits, tt.tool); got != tt.want {
+			t.Errorf("callLimit(%q) = %d, want %d", tt.tool, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+)
+
+// grantKey is the context key of the caller's grant for the current tool call
+type grantKey struct{}
+
+// rbac maps caller identities to the roles granted to them
+type rbac struct {
+	roles map[string]config.RoleConfig
+}
+
+// enabled reports whether roles are configured
+func (r *rbac) enabled() bool {
+	return len(r.roles) > 0
+}
+
+// grant returns the permissions of a caller, or nil if access is unrestricted
+func (r *rbac) grant(identity string) *grant {
+	if !r.enabled() {
+		return nil
+	}
+
+	names := make([]string, 0, len(r.roles))
+	for name := range r.roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	g := &grant{identity: identity}
+	for _, name := range names {
+		if matchesAny(r.roles[name].Identities, identity) {
+			g.roles = append(g.roles, r.roles[name])
+		}
+	}
+	return g
+}
+
+// grant holds the roles of a caller. A nil grant allows everything.
+type grant struct {
+	identity string
+	roles    []config.RoleConfig
+}
+
+// grantFromContext returns the grant stored by the authorization middleware
+func grantFromContext(ctx context.Context) *grant {
+	g, _ := ctx.Value(grantKey{}).(*grant)
+	return g
+}
+
+// forTool returns the grant restricted to the roles that allow a tool
+func (g *grant) forTool(tool string) *grant {
+	if g == nil {
+		return nil
+	}
+	restricted := &grant{identity: g.identity}
+	for _, role := range g.roles {
+		if matchesOrEmpty(role.Tools, tool) {
+			restricted.roles = append(restricted.roles, role)
+		}
+	}
+	return restricted
+}
+
+// allows reports whether any role of the grant remains
+func (g *grant) allows() bool {
+	return g == nil || len(g.roles) > 0
+}
+
+// allowsScope reports whether the caller may access a project in a region
+func (g *grant) allowsScope(region, projectID string) bool {
+	if g == nil {
+		return true
+	}
+	for _, role := range g.roles {
+		if matchesOrEmpty(role.Regions, region) && matchesOrEmpty(role.Projects, projectID) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsTag reports whether the caller may see a resource with the given tag
+func (g *grant) allowsTag(region, projectID, tag string) bool {
+	if g == nil {
+		return true
+	}
+	for _, role := range g.roles {
+		if matchesOrEmpty(role.Regions, region) && matchesOrEmpty(role.Projects, projectID) &&
+			matchesOrEmpty(role.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkScope returns a permission error unless the caller may use the client's project and region
+func (g *grant) checkScope(client *ucloud.UCloudClient) error {
+	if g.allowsScope(client.Region(), client.ProjectID()) {
+		return nil
+	}
+	return fmt.Errorf("permission denied: %s may not access project %s in region %s",
+		g.identity, client.ProjectID(), client.Region())
+}
+
+// matchesOrEmpty reports whether a name matches any pattern, where no patterns match everything
+func matchesOrEmpty(patterns []string, name string) bool {
+	return len(patterns) == 0 || matchesAny(patterns, name)
+}
+
+// withGrant stores the caller's grant in the context of a resource read,
+// where the scope and tag restrictions of all the caller's roles apply
+func (h *Handlers) withGrant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, grantKey{}, h.rbac.grant(IdentityFromContext(ctx)))
+}
+
+// authorize wraps a tool handler with role-based access control. The caller's
+// grant for the tool is stored in the context for scope and tag checks.
+func (h *Handlers) authorize(tool string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		identity := IdentityFromContext(ctx)
+		g := h.rbac.grant(identity).forTool(tool)
+		if !g.allows() {
+			log.Printf("Permission denied: %s may not call %s", identity, tool)
+			return mcp.NewToolResultError(fmt.Sprintf("permission denied: %s may not call %s", identity, tool)), nil
+		}
+		return next(context.WithValue(ctx, grantKey{}, g), request)
+	}
+}
@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+)
+
+func TestGrantAllowsTag(t *testing.T) {
+	r := &rbac{roles: map[string]config.RoleConfig{
+		"oncall": {
+			Identities: []string{"oncall-bot"},
+			Tools:      []string{"*"},
+			Regions:    []string{"cn-bj2"},
+			Tags:       []string{"web", "db-*"},
+		},
+		"intern": {
+			Identities: []string{"intern-*"},
+			Tools:      []string{"list_*"},
+			Projects:   []string{"org-dev"},
+		},
+	}}
+
+	tests := []struct {
+		name      string
+		identity  string
+		tool      string
+		region    string
+		projectID string
+		tag       string
+		want      bool
+	}{
+		{"matching tag", "oncall-bot", "reboot_instance", "cn-bj2", "org-prod", "web", true},
+		{"tag glob", "oncall-bot", "reboot_instance", "cn-bj2", "org-prod", "db-mysql", true},
+		{"other tag", "oncall-bot", "reboot_instance", "cn-bj2", "org-prod", "batch", false},
+		{"other region", "oncall-bot", "reboot_instance", "cn-sh2", "org-prod", "web", false},
+		{"role without tags sees every tag", "intern-alice", "list_instances", "cn-sh2", "org-dev", "batch", true},
+		{"other project", "intern-alice", "list_instances", "cn-sh2", "org-prod", "batch", false},
+		{"tool not granted", "intern-alice", "reboot_instance", "cn-sh2", "org-dev", "batch", false},
+		{"unknown identity", "mallory", "list_instances", "cn-bj2", "org-dev", "web", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := r.grant(tt.identity).forTool(tt.tool)
+			if got := g.allows() && g.allowsTag(tt.region, tt.projectID, tt.tag); got != tt.want {
+				t.Errorf("allowsTag(%q, %q, %q) for %s calling %s = %v, want %v",
+					tt.region, tt.projectID, tt.tag, tt.identity, tt.tool, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGrantWithoutRoles(t *testing.T) {
+	r := &rbac{}
+	g := r.grant("anyone").forTool("reboot_instance")
+	if g != nil {
+		t.Fatalf("grant without roles = %+v, want nil", g)
+	}
+	if !g.allows() || !g.allowsScope("cn-bj2", "org-prod") || !g.allowsTag("cn-bj2", "org-prod", "web") {
+		t.Errorf("a nil grant must allow everything")
+	}
+}
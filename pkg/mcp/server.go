@@ -2,42 +2,75 @@ package mcp
 
 import (
 	"context"
+	"expvar"
 	"fmt"
 	"log"
+	"net/http"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/ucloud/ucloud-mcp-server/pkg/audit"
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
 	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+	"github.com/ucloud/ucloud-mcp-server/pkg/version"
 )
 
 // MCPServer wraps the MCP server implementation
 type MCPServer struct {
-	server       *server.MCPServer
-	sseServer    *server.SSEServer
-	handlers     *Handlers
-	ucloudClient *ucloud.UCloudClient
+	server    *server.MCPServer
+	sseServer *server.SSEServer
+	handlers  *Handlers
+	auth      *authenticator
+	policy    *policy
+	audit     *audit.Logger
+	transport config.TransportConfig
 }
 
 // NewMCPServer creates a new MCP server instance
-func NewMCPServer(ucloudClient *ucloud.UCloudClient) *MCPServer {
+func NewMCPServer(clients *ucloud.ClientSet, cfg *config.Config) (*MCPServer, error) {
 	// Create MCP server
 	mcpServer := server.NewMCPServer(
-		"UCloud Instance Manager",
-		"1.0.0",
+		cfg.ServerName(),
+		version.Version,
 		server.WithResourceCapabilities(true, true),
 		server.WithLogging(),
 	)
 
-	handlers := NewHandlers(ucloudClient)
+	handlers := NewHandlers(clients, cfg.Roles)
 
-	return &MCPServer{
-		server:       mcpServer,
-		handlers:     handlers,
-		ucloudClient: ucloudClient,
+	auth, err := newAuthenticator(cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	auditLogger, err := audit.NewLogger(cfg.Audit)
+	if err != nil {
+		return nil, err
+	}
+
+	handlers.approvals, err = newApprovalQueue(cfg.Approval, auditLogger)
+	if err != nil {
+		return nil, err
 	}
+	handlers.quotas = newQuotaTracker(cfg.Quotas)
+	handlers.dryRun = cfg.Policy.DryRun
+
+	return &MCPServer{
+		server:    mcpServer,
+		handlers:  handlers,
+		auth:      auth,
+		policy:    &policy{cfg: cfg.Policy},
+		audit:     auditLogger,
+		transport: cfg.Transport,
+	}, nil
+}
+
+// UpdateClients swaps in new UCloud clients without interrupting active sessions
+func (s *MCPServer) UpdateClients(clients *ucloud.ClientSet) {
+	s.handlers.SetClients(clients)
 }
 
-// RegisterTools registers all tools
+// RegisterTools registers all tools allowed by the policy
 func (s *MCPServer) RegisterTools() {
 	// Add describe instance tool
 	describeTool := mcp.NewTool("describe_instance",
@@ -46,8 +79,13 @@ func (s *MCPServer) RegisterTools() {
 			mcp.Required(),
 			mcp.Description("ID of the instance to describe"),
 		),
+		mcp.WithBoolean("include_firewall",
+			mcp.Description("Include the rules of the firewall applied to the instance"),
+		),
+		withProfile(),
+		withProjectID(),
 	)
-	s.server.AddTool(describeTool, s.handlers.DescribeInstanceHandler)
+	s.addTool(describeTool, s.handlers.DescribeInstanceHandler, readOnlyTool)
 
 	// Add monitoring metrics tool
 	monitorTool := mcp.NewTool("get_instance_metrics",
@@ -56,8 +94,10 @@ func (s *MCPServer) RegisterTools() {
 			mcp.Required(),
 			mcp.Description("ID of the instance to monitor"),
 		),
+		withProfile(),
+		withProjectID(),
 	)
-	s.server.AddTool(monitorTool, s.handlers.GetInstanceMetricsHandler)
+	s.addTool(monitorTool, s.handlers.GetInstanceMetricsHandler, readOnlyTool)
 
 	// Add instance status tool
 	instanceStatusTool := mcp.NewTool("instance_status",
@@ -66,8 +106,11 @@ func (s *MCPServer) RegisterTools() {
 			mcp.Required(),
 			mcp.Description("Dummy parameter for no-parameter tools"),
 		),
+		withProfile(),
+		withProjectID(),
+		withAllProjects(),
 	)
-	s.server.AddTool(instanceStatusTool, s.handlers.InstanceStatusToolHandler)
+	s.addTool(instanceStatusTool, s.handlers.InstanceStatusToolHandler, readOnlyTool)
 
 	// Add instance list tool
 	instanceListTool := mcp.NewTool("instance_list",
@@ -76,28 +119,153 @@ func (s *MCPServer) RegisterTools() {
 			mcp.Required(),
 			mcp.Description("Dummy parameter for no-parameter tools"),
 		),
+		withProfile(),
+		withProjectID(),
+		withAllProjects(),
+	)
+	s.addTool(instanceListTool, s.handlers.InstanceListToolHandler, readOnlyTool)
+
+	// Add project list tool
+	listProjectsTool := mcp.NewTool("list_projects",
+		mcp.WithDescription("List the UCloud projects the server may operate on"),
+		mcp.WithString("random_string",
+			mcp.Required(),
+			mcp.Description("Dummy parameter for no-parameter tools"),
+		),
+		withProfile(),
+	)
+	s.addTool(listProjectsTool, s.handlers.ListProjectsToolHandler, readOnlyTool)
+
+	// Add profile list tool
+	listProfilesTool := mcp.NewTool("list_profiles",
+		mcp.WithDescription("List the named credential profiles configured on the server"),
+		mcp.WithString("random_string",
+			mcp.Required(),
+			mcp.Description("Dummy parameter for no-parameter tools"),
+		),
+	)
+	s.addTool(listProfilesTool, s.handlers.ListProfilesToolHandler, readOnlyTool)
+
+	s.registerDiskTools()
+	s.registerSnapshotTools()
+	s.registerEIPTools()
+	s.registerFirewallTools()
+	s.registerVPCTools()
+	s.registerLoadBalancerTools()
+	s.registerDatabaseTools()
+	s.registerCacheTools()
+	s.registerObjectStorageTools()
+	s.registerK8STools()
+
+	// Add operation status tool for calls waiting for approval
+	if s.handlers.approvals.enabled() {
+		operationStatusTool := mcp.NewTool("get_operation_status",
+			mcp.WithDescription("Get the status and result of an operation waiting for human approval"),
+			mcp.WithString("operation_id",
+				mcp.Required(),
+				mcp.Description("ID of the operation returned by the mutating tool"),
+			),
+		)
+		s.addTool(operationStatusTool, s.handlers.GetOperationStatusToolHandler, readOnlyTool)
+	}
+}
+
+// addTool registers a tool unless the policy disables it.
+// Mutating tools also accept the dry_run argument.
+func (s *MCPServer) addTool(tool mcp.Tool, handler server.ToolHandlerFunc, access toolAccess) {
+	if !s.policy.allowsTool(tool.Name, access) {
+		log.Printf("Tool %s disabled by policy", tool.Name)
+		return
+	}
+	if access == mutatingTool {
+		withDryRun()(&tool)
+	}
+	chain := audited(s.audit, tool.Name, s.handlers.authorize(tool.Name, s.handlers.limit(tool.Name, handler)))
+	if access == mutatingTool {
+		s.handlers.approvals.register(tool.Name, chain)
+	}
+	s.server.AddTool(tool, chain)
+}
+
+// addResource registers a resource unless the policy disables it
+func (s *MCPServer) addResource(resource mcp.Resource, handler server.ResourceHandlerFunc) {
+	if !s.policy.allowsResource(resource.Name) {
+		log.Printf("Resource %s disabled by policy", resource.Name)
+		return
+	}
+	s.server.AddResource(resource, handler)
+}
+
+// addResourceTemplate registers a resource template unless the policy disables it
+func (s *MCPServer) addResourceTemplate(template mcp.ResourceTemplate, handler server.ResourceTemplateHandlerFunc) {
+	if !s.policy.allowsResource(template.Name) {
+		log.Printf("Resource %s disabled by policy", template.Name)
+		return
+	}
+	s.server.AddResourceTemplate(template, handler)
+}
+
+// withProfile adds the optional profile argument accepted by every UCloud tool
+func withProfile() mcp.ToolOption {
+	return mcp.WithString("profile",
+		mcp.Description("Name of the credential profile to use (defaults to the active profile)"),
+	)
+}
+
+// withProjectID adds the optional project_id argument accepted by every UCloud tool
+func withProjectID() mcp.ToolOption {
+	return mcp.WithString("project_id",
+		mcp.Description("ID of the UCloud project to operate on (defaults to the configured project)"),
+	)
+}
+
+// withDryRun adds the optional dry_run argument accepted by every mutating tool
+func withDryRun() mcp.ToolOption {
+	return mcp.WithBoolean("dry_run",
+		mcp.Description("Validate the call and return the UCloud API requests it would send, without sending them"),
+	)
+}
+
+// withConfirm adds the confirm argument of tools that make disruptive changes
+func withConfirm() mcp.ToolOption {
+	return mcp.WithBoolean("confirm",
+		mcp.Description("Must be true to make the change, acknowledging the disruption it causes"),
 	)
-	s.server.AddTool(instanceListTool, s.handlers.InstanceListToolHandler)
 }
 
-// RegisterResources registers all resources
+// withAllProjects adds the optional all_projects argument for aggregated views
+func withAllProjects() mcp.ToolOption {
+	return mcp.WithBoolean("all_projects",
+		mcp.Description("Aggregate results across all allowed projects"),
+	)
+}
+
+// RegisterResources registers all resources allowed by the policy
 func (s *MCPServer) RegisterResources() {
 	// Add instance status resource
 	instanceStatusURI := "uhost://instances/{instance_id}/status"
-	s.server.AddResource(mcp.NewResource(instanceStatusURI, "instance_status",
+	s.addResource(mcp.NewResource(instanceStatusURI, "instance_status",
 		mcp.WithResourceDescription("Get the current status of a UCloud instance"),
 		mcp.WithMIMEType("application/json"),
 	), s.handlers.InstanceStatusHandler)
 
 	// Add instance list resource
-	s.server.AddResource(mcp.NewResource("uhost://instances", "instance_list",
+	s.addResource(mcp.NewResource("uhost://instances", "instance_list",
 		mcp.WithResourceDescription("List all UCloud instances"),
 		mcp.WithMIMEType("application/json"),
 	), s.handlers.InstanceListHandler)
+
+	s.registerDiskResources()
+	s.registerVPCResources()
 }
 
-// RegisterPrompts registers all prompts
+// RegisterPrompts registers all prompts allowed by the policy
 func (s *MCPServer) RegisterPrompts() {
+	if !s.policy.allowsPrompt("instance_management") {
+		log.Printf("Prompt instance_management disabled by policy")
+		return
+	}
+
 	// Add instance management prompt
 	s.server.AddPrompt(mcp.NewPrompt("instance_management",
 		mcp.WithPromptDescription("Help with UCloud instance management"),
@@ -161,8 +329,37 @@ func (s *MCPServer) Start(port string) error {
 	s.RegisterResources()
 	s.RegisterPrompts()
 
-	// Create and start SSE server
-	s.sseServer = server.NewSSEServer(s.server, " http://localhost:"+port)
+	if s.audit != nil {
+		log.Printf("Audit logging enabled")
+	}
+	if s.handlers.rbac.enabled() {
+		log.Printf("Role-based access control enabled with %d role(s)", len(s.handlers.rbac.roles))
+	}
+	if s.handlers.approvals.enabled() {
+		go func() {
+			if err := s.handlers.approvals.serve(); err != nil {
+				log.Printf("Approval endpoint error: %v", err)
+			}
+		}()
+	}
+	if s.handlers.dryRun {
+		log.Printf("Dry-run mode: tools that change UCloud resources only report the requests they would send")
+	}
+	if s.policy.cfg.ReadOnly {
+		log.Printf("Read-only mode: tools that change UCloud resources are disabled")
+	}
+
+	baseURL := s.transport.BaseURL
+	if baseURL == "" {
+		baseURL = "http://localhost:" + port
+	}
+
+	// Create and start SSE server behind the authentication middleware
+	s.sseServer = server.NewSSEServer(s.server, baseURL)
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", s.auth.authenticatedOrLocal(expvar.Handler()))
+	mux.Handle("/", s.sseServer)
+
 	log.Printf("SSE server listening on :%s", port)
-	return s.sseServer.Start(":" + port)
+	return http.ListenAndServe(":"+port, s.auth.wrap(mux))
 }
@@ -0,0 +1,321 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+	"github.com/ucloud/ucloud-sdk-go/services/udisk"
+)
+
+// registerSnapshotTools registers the disk snapshot tools
+func (s *MCPServer) registerSnapshotTools() {
+	createSnapshotTool := mcp.NewTool("create_snapshot",
+		mcp.WithDescription("Snapshot a cloud disk, or the system disk of an instance"),
+		mcp.WithString("disk_id",
+			mcp.Description("ID of the disk to snapshot"),
+		),
+		mcp.WithString("instance_id",
+			mcp.Description("ID of the instance whose system disk to snapshot, instead of disk_id"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the snapshot (defaults to one derived from the disk and time)"),
+		),
+		mcp.WithString("comment",
+			mcp.Description("Comment stored with the snapshot"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(createSnapshotTool, s.handlers.CreateSnapshotToolHandler, mutatingTool)
+
+	listSnapshotsTool := mcp.NewTool("list_snapshots",
+		mcp.WithDescription("List disk snapshots with their size and age"),
+		mcp.WithString("disk_id",
+			mcp.Description("Only list snapshots of this disk"),
+		),
+		mcp.WithString("instance_id",
+			mcp.Description("Only list snapshots of this instance's system disk"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(listSnapshotsTool, s.handlers.ListSnapshotsToolHandler, readOnlyTool)
+
+	restoreSnapshotTool := mcp.NewTool("restore_snapshot",
+		mcp.WithDescription("Roll a disk back to a snapshot. The disk must be detached, or its instance stopped."),
+		mcp.WithString("snapshot_id",
+			mcp.Required(),
+			mcp.Description("ID of the snapshot to restore"),
+		),
+		withAutoSnapshot(),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(restoreSnapshotTool, s.handlers.RestoreSnapshotToolHandler, mutatingTool)
+
+	deleteSnapshotTool := mcp.NewTool("delete_snapshot",
+		mcp.WithDescription("Delete a disk snapshot"),
+		mcp.WithString("snapshot_id",
+			mcp.Required(),
+			mcp.Description("ID of the snapshot to delete"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(deleteSnapshotTool, s.handlers.DeleteSnapshotToolHandler, mutatingTool)
+}
+
+// withAutoSnapshot adds the optional auto_snapshot argument of tools that overwrite disk contents or size
+func withAutoSnapshot() mcp.ToolOption {
+	return mcp.WithBoolean("auto_snapshot",
+		mcp.Description("Snapshot the disk before making the change"),
+	)
+}
+
+// takeAutoSnapshot snapshots a disk before a change and waits until the snapshot
+// is usable, so the change cannot start before there is something to roll back to
+func takeAutoSnapshot(ctx context.Context, client *ucloud.UCloudClient, disk *udisk.UDiskDataSet, reason string) (string, error) {
+	snapshotID, err := client.CreateSnapshot(disk, snapshotName(disk, reason), "")
+	if err != nil {
+		return "", err
+	}
+	if err := client.WaitForSnapshot(ctx, snapshotID); err != nil {
+		return snapshotID, fmt.Errorf("disk %s was not changed: %v", disk.UDiskId, err)
+	}
+	return snapshotID, nil
+}
+
+// CreateSnapshotToolHandler handles snapshot creation requests
+func (h *Handlers) CreateSnapshotToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	disk, err := resolveDisk(ctx, client, getStringArg(request, "disk_id"), getStringArg(request, "instance_id"))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	name := getStringArg(request, "name")
+	if name == "" {
+		name = snapshotName(disk, "manual")
+	}
+	comment := getStringArg(request, "comment")
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: []target{{ID: disk.UDiskId, Tag: disk.Tag}},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			snapshotID, err := client.CreateSnapshot(disk, name, comment)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(map[string]interface{}{
+				"snapshot_id": snapshotID,
+				"name":        name,
+				"disk_id":     disk.UDiskId,
+				"size":        disk.Size,
+			})
+		},
+	})
+}
+
+// ListSnapshotsToolHandler handles snapshot list requests
+func (h *Handlers) ListSnapshotsToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	diskID := getStringArg(request, "disk_id")
+	instanceID := getStringArg(request, "instance_id")
+
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if diskID != "" || instanceID != "" {
+		disk, err := resolveDisk(ctx, client, diskID, instanceID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		diskID = disk.UDiskId
+	}
+
+	snapshots, err := client.ListSnapshots(diskID)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Without a disk filter, only show snapshots of disks the caller may see
+	var visible map[string]bool
+	if diskID == "" && isRestricted(ctx, client) {
+		disks, err := client.ListDisks("")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		visible = make(map[string]bool)
+		for _, disk := range disks {
+			if isTagVisible(ctx, client, disk.Tag) {
+				visible[disk.UDiskId] = true
+			}
+		}
+	}
+
+	var allSnapshots []interface{}
+	for _, snapshot := range snapshots {
+		if visible != nil && !visible[snapshot.UDiskId] {
+			continue
+		}
+		snapshotCopy := snapshot // Create a copy to avoid using loop variable reference
+		info := ucloud.FormatSnapshotInfo(&snapshotCopy)
+		info.ProjectID = client.ProjectID()
+		allSnapshots = append(allSnapshots, info)
+	}
+
+	log.Printf("Total snapshots found: %d", len(allSnapshots))
+
+	jsonData, err := json.MarshalIndent(allSnapshots, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal snapshot data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// RestoreSnapshotToolHandler handles snapshot restore requests
+func (h *Handlers) RestoreSnapshotToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, snapshot, disk, result := h.snapshotForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+	if disk == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Disk %s of snapshot %s no longer exists",
+			snapshot.UDiskId, snapshot.SnapshotId)), nil
+	}
+	autoSnapshot := getBoolArg(request, "auto_snapshot")
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: []target{{ID: disk.UDiskId, Tag: disk.Tag}},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			response := map[string]interface{}{
+				"snapshot_id": snapshot.SnapshotId,
+				"disk_id":     disk.UDiskId,
+				"restored":    true,
+			}
+			if autoSnapshot {
+				snapshotID, err := takeAutoSnapshot(ctx, client, disk, "restore")
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				response["auto_snapshot_id"] = snapshotID
+			}
+			if err := client.RestoreSnapshot(snapshot); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(response)
+		},
+	})
+}
+
+// DeleteSnapshotToolHandler handles snapshot deletion requests
+func (h *Handlers) DeleteSnapshotToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, snapshot, disk, result := h.snapshotForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	t := target{ID: snapshot.SnapshotId}
+	if disk != nil {
+		t.Tag = disk.Tag
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: []target{t},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			if err := client.DeleteSnapshot(snapshot); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(map[string]interface{}{
+				"snapshot_id": snapshot.SnapshotId,
+				"deleted":     true,
+			})
+		},
+	})
+}
+
+// snapshotForRequest returns the client, the snapshot named by the snapshot_id
+// argument and its disk, or an error result if the caller may not see the disk.
+// The disk is nil when it was deleted and the caller is unrestricted.
+func (h *Handlers) snapshotForRequest(ctx context.Context, request mcp.CallToolRequest) (*ucloud.UCloudClient, *udisk.UDiskSnapshotSet, *udisk.UDiskDataSet, *mcp.CallToolResult) {
+	snapshotID := getStringArg(request, "snapshot_id")
+
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return nil, nil, nil, mcp.NewToolResultError(err.Error())
+	}
+
+	snapshot, err := client.DescribeSnapshot(snapshotID)
+	if err != nil {
+		return nil, nil, nil, mcp.NewToolResultError(err.Error())
+	}
+
+	disk, err := client.DescribeDisk(snapshot.UDiskId)
+	if err != nil {
+		if isRestricted(ctx, client) {
+			return nil, nil, nil, mcp.NewToolResultError(fmt.Sprintf("snapshot %s not found", snapshotID))
+		}
+		return client, snapshot, nil, nil
+	}
+	if err := checkDiskTag(ctx, client, disk); err != nil {
+		return nil, nil, nil, mcp.NewToolResultError(err.Error())
+	}
+	return client, snapshot, disk, nil
+}
+
+// resolveDisk returns a disk by ID, or the system disk of an instance.
+// The caller must be allowed to see the disk.
+func resolveDisk(ctx context.Context, client *ucloud.UCloudClient, diskID, instanceID string) (*udisk.UDiskDataSet, error) {
+	var disk *udisk.UDiskDataSet
+	var err error
+	switch {
+	case diskID != "":
+		disk, err = client.DescribeDisk(diskID)
+	case instanceID != "":
+		instance, err := client.DescribeInstance(instanceID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkInstanceTag(ctx, client, instance); err != nil {
+			return nil, err
+		}
+		disk, err = client.SystemDisk(instanceID)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("disk_id or instance_id is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkDiskTag(ctx, client, disk); err != nil {
+		return nil, err
+	}
+	return disk, nil
+}
+
+// isRestricted reports whether the scope or the caller's roles hide some resources
+func isRestricted(ctx context.Context, client *ucloud.UCloudClient) bool {
+	return client.IsScoped() || grantFromContext(ctx) != nil
+}
+
+// snapshotName returns a snapshot name identifying the disk, the reason and the time
+func snapshotName(disk *udisk.UDiskDataSet, reason string) string {
+	return fmt.Sprintf("%s-%s-%s", reason, disk.UDiskId, time.Now().UTC().Format("20060102-150405"))
+}
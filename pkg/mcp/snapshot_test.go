@@ -0,0 +1,33 @@
+package mcp
+
+import "testing"
+
+const testSnapshot = `{"SnapshotId":"snap-test","UDiskId":"bs-test","Zone":"cn-bj2-02","Name":"manual","Status":"Normal","Size":20}`
+
+func TestSnapshotToolsDryRun(t *testing.T) {
+	responses := map[string]string{
+		"DescribeUDisk":         `{"RetCode":0,"DataSet":[` + testDisk + `]}`,
+		"DescribeUDiskSnapshot": `{"RetCode":0,"DataSet":[` + testSnapshot + `]}`,
+	}
+
+	testDryRuns(t, responses, []dryRunCase{
+		{
+			tool:    "create_snapshot",
+			handler: (*Handlers).CreateSnapshotToolHandler,
+			args:    map[string]interface{}{"disk_id": "bs-test"},
+			want:    []string{"CreateUDiskSnapshot"},
+		},
+		{
+			tool:    "restore_snapshot",
+			handler: (*Handlers).RestoreSnapshotToolHandler,
+			args:    map[string]interface{}{"snapshot_id": "snap-test", "auto_snapshot": true},
+			want:    []string{"CreateUDiskSnapshot", "RestoreUDisk"},
+		},
+		{
+			tool:    "delete_snapshot",
+			handler: (*Handlers).DeleteSnapshotToolHandler,
+			args:    map[string]interface{}{"snapshot_id": "snap-test"},
+			want:    []string{"DeleteUDiskSnapshot"},
+		},
+	})
+}
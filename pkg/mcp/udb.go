@@ -0,0 +1,308 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+	"github.com/ucloud/ucloud-sdk-go/services/udb"
+)
+
+// registerDatabaseTools registers the UDB tools
+func (s *MCPServer) registerDatabaseTools() {
+	listDatabasesTool := mcp.NewTool("list_databases",
+		mcp.WithDescription("List UCloud UDB MySQL instances"),
+		withProfile(),
+		withProjectID(),
+		withAllProjects(),
+	)
+	s.addTool(listDatabasesTool, s.handlers.ListDatabasesToolHandler, readOnlyTool)
+
+	describeDatabaseTool := mcp.NewTool("describe_database",
+		mcp.WithDescription("Get a UDB instance with its version, role, replicas and disk usage"),
+		mcp.WithString("db_id",
+			mcp.Required(),
+			mcp.Description("ID of the database to describe"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(describeDatabaseTool, s.handlers.DescribeDatabaseToolHandler, readOnlyTool)
+
+	metricsTool := mcp.NewTool("get_database_metrics",
+		mcp.WithDescription("Get monitoring metrics for a UDB instance"),
+		mcp.WithString("db_id",
+			mcp.Required(),
+			mcp.Description("ID of the database"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(metricsTool, s.handlers.GetDatabaseMetricsToolHandler, readOnlyTool)
+
+	restartDatabaseTool := mcp.NewTool("restart_database",
+		mcp.WithDescription("Restart a UDB instance. The database is unavailable while it restarts."),
+		mcp.WithString("db_id",
+			mcp.Required(),
+			mcp.Description("ID of the database to restart"),
+		),
+		withConfirm(),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(restartDatabaseTool, s.handlers.RestartDatabaseToolHandler, mutatingTool)
+
+	createBackupTool := mcp.NewTool("create_database_backup",
+		mcp.WithDescription("Start a manual backup of a UDB instance"),
+		mcp.WithString("db_id",
+			mcp.Required(),
+			mcp.Description("ID of the database to back up"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the backup (defaults to one derived from the database and time)"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(createBackupTool, s.handlers.CreateDatabaseBackupToolHandler, mutatingTool)
+
+	listBackupsTool := mcp.NewTool("list_database_backups",
+		mcp.WithDescription("List UDB backups with their size, state and age"),
+		mcp.WithString("db_id",
+			mcp.Description("Only list backups of this database"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(listBackupsTool, s.handlers.ListDatabaseBackupsToolHandler, readOnlyTool)
+}
+
+// ListDatabasesToolHandler handles database list tool requests
+func (h *Handlers) ListDatabasesToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	clients, err := h.clientsForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var allDatabases []interface{}
+	for _, client := range clients {
+		databases, err := client.ListDatabases()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list databases in project %s: %v", client.ProjectID(), err)), nil
+		}
+
+		for _, db := range databases {
+			if !isTagVisible(ctx, client, db.Tag) {
+				continue
+			}
+			dbCopy := db // Create a copy to avoid using loop variable reference
+			info := ucloud.FormatDatabaseInfo(&dbCopy)
+			info.ProjectID = client.ProjectID()
+			allDatabases = append(allDatabases, info)
+		}
+	}
+
+	log.Printf("Total databases found: %d", len(allDatabases))
+
+	jsonData, err := json.MarshalIndent(allDatabases, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal database data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// DescribeDatabaseToolHandler handles database description requests
+func (h *Handlers) DescribeDatabaseToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, db, result := h.databaseForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	info := ucloud.FormatDatabaseInfo(db)
+	info.ProjectID = client.ProjectID()
+
+	jsonData, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal database info: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// GetDatabaseMetricsToolHandler handles database metrics retrieval requests
+func (h *Handlers) GetDatabaseMetricsToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, db, result := h.databaseForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	metrics, err := client.GetDatabaseMetrics(db)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get metrics: %v", err)), nil
+	}
+	if metrics == nil {
+		return mcp.NewToolResultError("Database metrics not found"), nil
+	}
+
+	return jsonResult(map[string]interface{}{
+		"db_id":      db.DBId,
+		"project_id": client.ProjectID(),
+		"name":       db.Name,
+		"status":     db.State,
+		"basic_info": map[string]interface{}{
+			"version":    db.DBTypeId,
+			"memory":     db.MemoryLimit,
+			"disk_space": db.DiskSpace,
+			"disk_used":  db.DiskUsedSize,
+			"zone":       db.Zone,
+			"ip":         db.VirtualIP,
+		},
+		"metrics":   metrics,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// RestartDatabaseToolHandler handles database restart requests
+func (h *Handlers) RestartDatabaseToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, db, result := h.databaseForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	disruption := fmt.Sprintf("Restarting %s interrupts every connection to it", db.DBId)
+	if len(db.DataSet) > 0 {
+		disruption += fmt.Sprintf(" and may affect the replication of its replicas (%d)", len(db.DataSet))
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:     client,
+		Targets:    []target{{ID: db.DBId, Tag: db.Tag}},
+		Disruption: disruption,
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			if err := client.RestartDatabase(db.DBId); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(map[string]interface{}{
+				"db_id":     db.DBId,
+				"restarted": true,
+			})
+		},
+	})
+}
+
+// CreateDatabaseBackupToolHandler handles manual database backup requests
+func (h *Handlers) CreateDatabaseBackupToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, db, result := h.databaseForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	name := getStringArg(request, "name")
+	if name == "" {
+		name = fmt.Sprintf("manual-%s-%s", db.DBId, time.Now().UTC().Format("20060102-150405"))
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: []target{{ID: db.DBId, Tag: db.Tag}},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			if err := client.CreateDatabaseBackup(db, name); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(map[string]interface{}{
+				"db_id":       db.DBId,
+				"backup_name": name,
+				"started":     true,
+			})
+		},
+	})
+}
+
+// ListDatabaseBackupsToolHandler handles database backup list requests
+func (h *Handlers) ListDatabaseBackupsToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	dbID := getStringArg(request, "db_id")
+
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if dbID != "" {
+		if _, err := describeVisibleDatabase(ctx, client, dbID); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	backups, err := client.ListDatabaseBackups(dbID)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Without a database filter, only show backups of databases the caller may see
+	var visible map[string]bool
+	if dbID == "" && isRestricted(ctx, client) {
+		databases, err := client.ListDatabases()
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		visible = make(map[string]bool)
+		for _, db := range databases {
+			if isTagVisible(ctx, client, db.Tag) {
+				visible[db.DBId] = true
+			}
+		}
+	}
+
+	var allBackups []interface{}
+	for _, backup := range backups {
+		if visible != nil && !visible[backup.DBId] {
+			continue
+		}
+		backupCopy := backup // Create a copy to avoid using loop variable reference
+		info := ucloud.FormatDatabaseBackupInfo(&backupCopy)
+		info.ProjectID = client.ProjectID()
+		allBackups = append(allBackups, info)
+	}
+
+	log.Printf("Total database backups found: %d", len(allBackups))
+
+	jsonData, err := json.MarshalIndent(allBackups, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal backup data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// databaseForRequest returns the client and the database named by the db_id argument,
+// or an error result if the database cannot be found or the caller may not see it
+func (h *Handlers) databaseForRequest(ctx context.Context, request mcp.CallToolRequest) (*ucloud.UCloudClient, *udb.UDBInstanceSet, *mcp.CallToolResult) {
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(err.Error())
+	}
+
+	db, err := describeVisibleDatabase(ctx, client, getStringArg(request, "db_id"))
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(err.Error())
+	}
+	return client, db, nil
+}
+
+// describeVisibleDatabase returns a database the caller may see
+func describeVisibleDatabase(ctx context.Context, client *ucloud.UCloudClient, dbID string) (*udb.UDBInstanceSet, error) {
+	db, err := client.DescribeDatabase(dbID)
+	if err != nil {
+		return nil, err
+	}
+	if !isTagVisible(ctx, client, db.Tag) {
+		return nil, fmt.Errorf("permission denied: database %s is outside the tags allowed for %s",
+			db.DBId, IdentityFromContext(ctx))
+	}
+	return db, nil
+}
@@ -0,0 +1,26 @@
+package mcp
+
+import "testing"
+
+const testDatabase = `{"DBId":"udb-test","Name":"orders","DBTypeId":"mysql-5.7","ClassType":"sql","Zone":"cn-bj2-02","State":"Running","Tag":"Default"}`
+
+func TestDatabaseToolsDryRun(t *testing.T) {
+	responses := map[string]string{
+		"DescribeUDBInstance": `{"RetCode":0,"TotalCount":1,"DataSet":[` + testDatabase + `]}`,
+	}
+
+	testDryRuns(t, responses, []dryRunCase{
+		{
+			tool:    "restart_database",
+			handler: (*Handlers).RestartDatabaseToolHandler,
+			args:    map[string]interface{}{"db_id": "udb-test"},
+			want:    []string{"RestartUDBInstance"},
+		},
+		{
+			tool:    "create_database_backup",
+			handler: (*Handlers).CreateDatabaseBackupToolHandler,
+			args:    map[string]interface{}{"db_id": "udb-test"},
+			want:    []string{"BackupUDBInstance"},
+		},
+	})
+}
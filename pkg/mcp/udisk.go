@@ -0,0 +1,436 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+	"github.com/ucloud/ucloud-mcp-server/pkg/utils"
+	"github.com/ucloud/ucloud-sdk-go/services/udisk"
+)
+
+// registerDiskTools registers the UDisk tools
+func (s *MCPServer) registerDiskTools() {
+	listDisksTool := mcp.NewTool("list_disks",
+		mcp.WithDescription("List UCloud cloud disks (UDisk)"),
+		mcp.WithString("instance_id",
+			mcp.Description("Only list disks attached to this instance"),
+		),
+		withProfile(),
+		withProjectID(),
+		withAllProjects(),
+	)
+	s.addTool(listDisksTool, s.handlers.ListDisksToolHandler, readOnlyTool)
+
+	describeDiskTool := mcp.NewTool("describe_disk",
+		mcp.WithDescription("Get information about a UCloud cloud disk"),
+		mcp.WithString("disk_id",
+			mcp.Required(),
+			mcp.Description("ID of the disk to describe"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(describeDiskTool, s.handlers.DescribeDiskToolHandler, readOnlyTool)
+
+	createDiskTool := mcp.NewTool("create_disk",
+		mcp.WithDescription("Create a UCloud cloud disk"),
+		mcp.WithString("zone",
+			mcp.Required(),
+			mcp.Description("Availability zone of the disk, e.g. cn-bj2-02"),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the disk"),
+		),
+		mcp.WithNumber("size",
+			mcp.Required(),
+			mcp.Description("Size of the disk in GB"),
+			mcp.Min(1),
+		),
+		mcp.WithString("disk_type",
+			mcp.Description("Disk type, e.g. DataDisk, SSDDataDisk or RSSDDataDisk (defaults to DataDisk)"),
+		),
+		mcp.WithString("tag",
+			mcp.Description("Business group tag of the disk"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(createDiskTool, s.handlers.CreateDiskToolHandler, mutatingTool)
+
+	attachDiskTool := mcp.NewTool("attach_disk",
+		mcp.WithDescription("Attach a cloud disk to an instance and wait until it is in use"),
+		mcp.WithString("disk_id",
+			mcp.Required(),
+			mcp.Description("ID of the disk to attach"),
+		),
+		mcp.WithString("instance_id",
+			mcp.Required(),
+			mcp.Description("ID of the instance to attach the disk to"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(attachDiskTool, s.handlers.AttachDiskToolHandler, mutatingTool)
+
+	detachDiskTool := mcp.NewTool("detach_disk",
+		mcp.WithDescription("Detach a cloud disk from its instance and wait until it is available"),
+		mcp.WithString("disk_id",
+			mcp.Required(),
+			mcp.Description("ID of the disk to detach"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(detachDiskTool, s.handlers.DetachDiskToolHandler, mutatingTool)
+
+	resizeDiskTool := mcp.NewTool("resize_disk",
+		mcp.WithDescription("Grow a cloud disk to a new size"),
+		mcp.WithString("disk_id",
+			mcp.Required(),
+			mcp.Description("ID of the disk to resize"),
+		),
+		mcp.WithNumber("size",
+			mcp.Required(),
+			mcp.Description("New size of the disk in GB, larger than the current size"),
+			mcp.Min(1),
+		),
+		withAutoSnapshot(),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(resizeDiskTool, s.handlers.ResizeDiskToolHandler, mutatingTool)
+
+	deleteDiskTool := mcp.NewTool("delete_disk",
+		mcp.WithDescription("Delete a detached cloud disk"),
+		mcp.WithString("disk_id",
+			mcp.Required(),
+			mcp.Description("ID of the disk to delete"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(deleteDiskTool, s.handlers.DeleteDiskToolHandler, mutatingTool)
+}
+
+// registerDiskResources registers the UDisk resources
+func (s *MCPServer) registerDiskResources() {
+	s.addResourceTemplate(mcp.NewResourceTemplate(diskURI, "disk",
+		mcp.WithTemplateDescription("Get information about a UCloud cloud disk"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), s.handlers.DiskResourceHandler)
+}
+
+// diskURI is the URI template of the disk resource
+const diskURI = "udisk://disks/{disk_id}"
+
+// ListDisksToolHandler handles disk list tool requests
+func (h *Handlers) ListDisksToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	hostID := getStringArg(request, "instance_id")
+
+	clients, err := h.clientsForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var allDisks []interface{}
+	for _, client := range clients {
+		disks, err := client.ListDisks(hostID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list disks in project %s: %v", client.ProjectID(), err)), nil
+		}
+
+		for _, disk := range disks {
+			if !isTagVisible(ctx, client, disk.Tag) {
+				continue
+			}
+			diskCopy := disk // Create a copy to avoid using loop variable reference
+			info := ucloud.FormatDiskInfo(&diskCopy)
+			info.ProjectID = client.ProjectID()
+			allDisks = append(allDisks, info)
+		}
+	}
+
+	log.Printf("Total disks found: %d", len(allDisks))
+
+	jsonData, err := json.MarshalIndent(allDisks, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal disk data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// DescribeDiskToolHandler handles disk description requests
+func (h *Handlers) DescribeDiskToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, disk, result := h.diskForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	return diskResult(client, disk)
+}
+
+// CreateDiskToolHandler handles disk creation requests
+func (h *Handlers) CreateDiskToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	spec := ucloud.DiskSpec{
+		Zone:     getStringArg(request, "zone"),
+		Name:     getStringArg(request, "name"),
+		Size:     getIntArg(request, "size"),
+		DiskType: getStringArg(request, "disk_type"),
+		Tag:      getStringArg(request, "tag"),
+	}
+	if spec.Size <= 0 {
+		return mcp.NewToolResultError("size must be a positive number of GB"), nil
+	}
+
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if !grantFromContext(ctx).allowsTag(client.Region(), client.ProjectID(), spec.Tag) {
+		return mcp.NewToolResultError(fmt.Sprintf("permission denied: tag %q is outside the tags allowed for %s",
+			spec.Tag, IdentityFromContext(ctx))), nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: []target{{ID: spec.Name, Tag: spec.Tag}},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			diskID, err := client.CreateDisk(spec)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(map[string]interface{}{
+				"disk_id": diskID,
+				"zone":    spec.Zone,
+				"size":    spec.Size,
+			})
+		},
+	})
+}
+
+// AttachDiskToolHandler handles disk attach requests
+func (h *Handlers) AttachDiskToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	instanceID := getStringArg(request, "instance_id")
+
+	client, disk, result := h.diskForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+	if disk.Status != ucloud.DiskStatusAvailable {
+		return mcp.NewToolResultError(fmt.Sprintf("Disk %s is %s, not %s", disk.UDiskId, disk.Status,
+			ucloud.DiskStatusAvailable)), nil
+	}
+
+	instance, err := client.DescribeInstance(instanceID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to describe instance %v: %v", instanceID, err)), nil
+	}
+	if err := checkInstanceTag(ctx, client, instance); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client: client,
+		Targets: []target{
+			{ID: disk.UDiskId, Tag: disk.Tag},
+			{ID: instance.UHostId, Tag: instance.Tag},
+		},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			if err := client.AttachDisk(disk, instance.UHostId); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return waitForDisk(ctx, client, disk.UDiskId, ucloud.DiskStatusInUse)
+		},
+	})
+}
+
+// DetachDiskToolHandler handles disk detach requests
+func (h *Handlers) DetachDiskToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, disk, result := h.diskForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+	if disk.Status != ucloud.DiskStatusInUse {
+		return mcp.NewToolResultError(fmt.Sprintf("Disk %s is %s, not %s", disk.UDiskId, disk.Status,
+			ucloud.DiskStatusInUse)), nil
+	}
+	if disk.IsBoot == "True" {
+		return mcp.NewToolResultError(fmt.Sprintf("Disk %s is the system disk of %s", disk.UDiskId, disk.HostId)), nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: []target{{ID: disk.UDiskId, Tag: disk.Tag}},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			if err := client.DetachDisk(disk); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return waitForDisk(ctx, client, disk.UDiskId, ucloud.DiskStatusAvailable)
+		},
+	})
+}
+
+// ResizeDiskToolHandler handles disk resize requests
+func (h *Handlers) ResizeDiskToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	size := getIntArg(request, "size")
+	autoSnapshot := getBoolArg(request, "auto_snapshot")
+
+	client, disk, result := h.diskForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+	if size <= disk.Size {
+		return mcp.NewToolResultError(fmt.Sprintf("New size %d GB must be larger than the current size %d GB",
+			size, disk.Size)), nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: []target{{ID: disk.UDiskId, Tag: disk.Tag}},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			response := map[string]interface{}{
+				"disk_id":       disk.UDiskId,
+				"previous_size": disk.Size,
+				"size":          size,
+			}
+			if autoSnapshot {
+				snapshotID, err := takeAutoSnapshot(ctx, client, disk, "resize")
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				response["auto_snapshot_id"] = snapshotID
+			}
+			if err := client.ResizeDisk(disk, size); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(response)
+		},
+	})
+}
+
+// DeleteDiskToolHandler handles disk deletion requests
+func (h *Handlers) DeleteDiskToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, disk, result := h.diskForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+	if disk.Status != ucloud.DiskStatusAvailable {
+		return mcp.NewToolResultError(fmt.Sprintf("Disk %s is %s; detach it before deleting", disk.UDiskId,
+			disk.Status)), nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: []target{{ID: disk.UDiskId, Tag: disk.Tag}},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			if err := client.DeleteDisk(disk); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(map[string]interface{}{
+				"disk_id": disk.UDiskId,
+				"deleted": true,
+			})
+		},
+	})
+}
+
+// DiskResourceHandler handles disk resource reads
+func (h *Handlers) DiskResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ctx = h.withGrant(ctx)
+	variables, err := utils.ParsePath(diskURI, request.Params.URI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse path: %v", err)
+	}
+
+	diskID := variables["disk_id"]
+	if diskID == "" {
+		return nil, fmt.Errorf("disk_id not found in path")
+	}
+
+	client, err := h.resourceClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	disk, err := client.DescribeDisk(diskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDiskTag(ctx, client, disk); err != nil {
+		return nil, err
+	}
+
+	info := ucloud.FormatDiskInfo(disk)
+	info.ProjectID = client.ProjectID()
+	jsonData, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
+		},
+	}, nil
+}
+
+// diskForRequest returns the client and the disk named by the disk_id argument,
+// or an error result if the disk cannot be found or the caller may not see it
+func (h *Handlers) diskForRequest(ctx context.Context, request mcp.CallToolRequest) (*ucloud.UCloudClient, *udisk.UDiskDataSet, *mcp.CallToolResult) {
+	diskID := getStringArg(request, "disk_id")
+
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(err.Error())
+	}
+
+	disk, err := client.DescribeDisk(diskID)
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(fmt.Sprintf("Failed to describe disk %v: %v", diskID, err))
+	}
+	if err := checkDiskTag(ctx, client, disk); err != nil {
+		return nil, nil, mcp.NewToolResultError(err.Error())
+	}
+	return client, disk, nil
+}
+
+// waitForDisk waits until a disk reaches a status and reports the disk
+func waitForDisk(ctx context.Context, client *ucloud.UCloudClient, diskID, status string) (*mcp.CallToolResult, error) {
+	disk, err := client.WaitForDiskStatus(ctx, diskID, status)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if disk == nil {
+		return jsonResult(map[string]interface{}{"disk_id": diskID})
+	}
+	return diskResult(client, disk)
+}
+
+// diskResult formats a disk as a tool result
+func diskResult(client *ucloud.UCloudClient, disk *udisk.UDiskDataSet) (*mcp.CallToolResult, error) {
+	info := ucloud.FormatDiskInfo(disk)
+	info.ProjectID = client.ProjectID()
+	jsonData, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal disk info: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// checkDiskTag returns a permission error unless the caller may see a disk
+func checkDiskTag(ctx context.Context, client *ucloud.UCloudClient, disk *udisk.UDiskDataSet) error {
+	if isTagVisible(ctx, client, disk.Tag) {
+		return nil
+	}
+	return fmt.Errorf("permission denied: disk %s is outside the tags allowed for %s",
+		disk.UDiskId, IdentityFromContext(ctx))
+}
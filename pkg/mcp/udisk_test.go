@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+)
+
+const (
+	testDisk         = `{"UDiskId":"bs-test","Zone":"cn-bj2-02","Name":"data","Size":20,"Status":"Available","Tag":"Default"}`
+	testAttachedDisk = `{"UDiskId":"bs-test","Zone":"cn-bj2-02","Name":"data","Size":20,"Status":"InUse","Tag":"Default","HostId":"uhost-test","IsBoot":"False"}`
+	testInstance     = `{"UHostId":"uhost-test","Zone":"cn-bj2-02","Name":"web","State":"Running","Tag":"Default"}`
+)
+
+func TestDiskToolsDryRun(t *testing.T) {
+	responses := map[string]string{
+		"DescribeUDisk":         `{"RetCode":0,"DataSet":[` + testDisk + `]}`,
+		"DescribeUHostInstance": `{"RetCode":0,"UHostSet":[` + testInstance + `]}`,
+	}
+	attached := map[string]string{
+		"DescribeUDisk": `{"RetCode":0,"DataSet":[` + testAttachedDisk + `]}`,
+	}
+
+	testDryRuns(t, responses, []dryRunCase{
+		{
+			tool:    "create_disk",
+			handler: (*Handlers).CreateDiskToolHandler,
+			args:    map[string]interface{}{"zone": "cn-bj2-02", "name": "data", "size": float64(20)},
+			want:    []string{"CreateUDisk"},
+		},
+		{
+			tool:    "attach_disk",
+			handler: (*Handlers).AttachDiskToolHandler,
+			args:    map[string]interface{}{"disk_id": "bs-test", "instance_id": "uhost-test"},
+			want:    []string{"AttachUDisk"},
+		},
+		{
+			tool:      "detach_disk",
+			handler:   (*Handlers).DetachDiskToolHandler,
+			args:      map[string]interface{}{"disk_id": "bs-test"},
+			responses: attached,
+			want:      []string{"DetachUDisk"},
+		},
+		{
+			tool:    "resize_disk",
+			handler: (*Handlers).ResizeDiskToolHandler,
+			args:    map[string]interface{}{"disk_id": "bs-test", "size": float64(40), "auto_snapshot": true},
+			want:    []string{"CreateUDiskSnapshot", "ResizeUDisk"},
+		},
+		{
+			tool:    "delete_disk",
+			handler: (*Handlers).DeleteDiskToolHandler,
+			args:    map[string]interface{}{"disk_id": "bs-test"},
+			want:    []string{"DeleteUDisk"},
+		},
+	})
+}
+
+func TestDetachDiskInScopeThroughHost(t *testing.T) {
+	// The disk is only in scope because it is attached to an instance in scope
+	var detached atomic.Bool
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := `{"RetCode":0}`
+		switch requestParams(r).Get("Action") {
+		case "DescribeUHostInstance":
+			body = `{"RetCode":0,"TotalCount":1,"UHostSet":[` + testInstance + `]}`
+		case "DescribeUDisk":
+			body = `{"RetCode":0,"DataSet":[` + testAttachedDisk + `]}`
+			if detached.Load() {
+				body = `{"RetCode":0,"DataSet":[` + testDisk + `]}`
+			}
+		case "DetachUDisk":
+			detached.Store(true)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	})
+	h := newTestHandlers(t, &config.Config{
+		Profile: testProfile(t, api),
+		Scope:   config.ScopeConfig{InstanceIDs: []string{"uhost-test"}},
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "detach_disk"
+	request.Params.Arguments = map[string]interface{}{"disk_id": "bs-test"}
+	result, err := h.DetachDiskToolHandler(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError {
+		t.Fatalf("detach_disk: %s", resultText(result))
+	}
+	if !detached.Load() {
+		t.Error("detach_disk did not detach the disk")
+	}
+}
@@ -0,0 +1,355 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+	"github.com/ucloud/ucloud-sdk-go/services/ufile"
+)
+
+const (
+	// defaultObjectTextBytes is how much of an object get_object_text reads by default
+	defaultObjectTextBytes = 64 * 1024
+
+	// maxObjectTextBytes is the most get_object_text reads of an object
+	maxObjectTextBytes = 1024 * 1024
+
+	// defaultPresignExpiry and maxPresignExpiry bound how long presigned URLs are valid, in seconds
+	defaultPresignExpiry = 15 * 60
+	maxPresignExpiry     = 60 * 60
+)
+
+// textContentTypes are non-text/* content types whose objects are text
+var textContentTypes = []string{
+	"application/json",
+	"application/xml",
+	"application/javascript",
+	"application/x-yaml",
+	"application/yaml",
+	"application/x-sh",
+	"application/x-ndjson",
+}
+
+// registerObjectStorageTools registers the US3 (UFile) tools
+func (s *MCPServer) registerObjectStorageTools() {
+	listBucketsTool := mcp.NewTool("list_buckets",
+		mcp.WithDescription("List UCloud US3 (UFile) object storage buckets"),
+		withProfile(),
+		withProjectID(),
+		withAllProjects(),
+	)
+	s.addTool(listBucketsTool, s.handlers.ListBucketsToolHandler, readOnlyTool)
+
+	listObjectsTool := mcp.NewTool("list_objects",
+		mcp.WithDescription("List objects in a US3 bucket. Pass next_marker of a truncated result as marker to get the next page."),
+		mcp.WithString("bucket",
+			mcp.Required(),
+			mcp.Description("Name of the bucket"),
+		),
+		mcp.WithString("prefix",
+			mcp.Description("Only list keys starting with this prefix"),
+		),
+		mcp.WithString("delimiter",
+			mcp.Description("Group keys up to the next occurrence of this delimiter into common prefixes, e.g. / to browse like directories"),
+		),
+		mcp.WithString("marker",
+			mcp.Description("Only list keys after this one"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of keys to return"),
+			mcp.DefaultNumber(100),
+			mcp.Min(1),
+			mcp.Max(1000),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(listObjectsTool, s.handlers.ListObjectsToolHandler, readOnlyTool)
+
+	headObjectTool := mcp.NewTool("head_object",
+		mcp.WithDescription("Get the size, content type and modification time of an object in a US3 bucket"),
+		mcp.WithString("bucket",
+			mcp.Required(),
+			mcp.Description("Name of the bucket"),
+		),
+		mcp.WithString("key",
+			mcp.Required(),
+			mcp.Description("Key of the object"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(headObjectTool, s.handlers.HeadObjectToolHandler, readOnlyTool)
+
+	getObjectTextTool := mcp.NewTool("get_object_text",
+		mcp.WithDescription("Read the start of a text object in a US3 bucket, e.g. a log file. Binary objects are refused."),
+		mcp.WithString("bucket",
+			mcp.Required(),
+			mcp.Description("Name of the bucket"),
+		),
+		mcp.WithString("key",
+			mcp.Required(),
+			mcp.Description("Key of the object"),
+		),
+		mcp.WithNumber("max_bytes",
+			mcp.Description("Maximum number of bytes to read"),
+			mcp.DefaultNumber(defaultObjectTextBytes),
+			mcp.Min(1),
+			mcp.Max(maxObjectTextBytes),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(getObjectTextTool, s.handlers.GetObjectTextToolHandler, readOnlyTool)
+
+	presignTool := mcp.NewTool("generate_presigned_url",
+		mcp.WithDescription("Generate a URL that allows anyone holding it to download an object until it expires"),
+		mcp.WithString("bucket",
+			mcp.Required(),
+			mcp.Description("Name of the bucket"),
+		),
+		mcp.WithString("key",
+			mcp.Required(),
+			mcp.Description("Key of the object"),
+		),
+		mcp.WithNumber("expires_in",
+			mcp.Description("Seconds until the URL expires"),
+			mcp.DefaultNumber(defaultPresignExpiry),
+			mcp.Min(1),
+			mcp.Max(maxPresignExpiry),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	// Handing out a URL grants access to the object, so it is treated as a change:
+	// it is disabled in read-only mode and goes through approvals
+	s.addTool(presignTool, s.handlers.GeneratePresignedURLToolHandler, mutatingTool)
+}
+
+// ListBucketsToolHandler handles bucket list tool requests
+func (h *Handlers) ListBucketsToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	clients, err := h.clientsForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var allBuckets []interface{}
+	for _, client := range clients {
+		buckets, err := client.ListBuckets()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list buckets in project %s: %v", client.ProjectID(), err)), nil
+		}
+
+		for _, bucket := range buckets {
+			if !isTagVisible(ctx, client, bucket.Tag) {
+				continue
+			}
+			bucketCopy := bucket // Create a copy to avoid using loop variable reference
+			info := ucloud.FormatBucketInfo(&bucketCopy)
+			info.ProjectID = client.ProjectID()
+			allBuckets = append(allBuckets, info)
+		}
+	}
+
+	log.Printf("Total buckets found: %d", len(allBuckets))
+
+	jsonData, err := json.MarshalIndent(allBuckets, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal bucket data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// ListObjectsToolHandler handles object list tool requests
+func (h *Handlers) ListObjectsToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	limit := getIntArg(request, "limit")
+	if limit <= 0 {
+		limit = 100
+	}
+
+	client, bucket, result := h.bucketForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	list, err := client.ListObjects(bucket, getStringArg(request, "prefix"), getStringArg(request, "delimiter"),
+		getStringArg(request, "marker"), limit)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	log.Printf("Total objects found: %d", len(list.Objects))
+
+	jsonData, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal object data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// HeadObjectToolHandler handles object metadata requests
+func (h *Handlers) HeadObjectToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, bucket, result := h.bucketForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	object, err := client.HeadObject(bucket, getStringArg(request, "key"))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	jsonData, err := json.MarshalIndent(object, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal object info: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// GetObjectTextToolHandler handles requests to read text objects
+func (h *Handlers) GetObjectTextToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	key := getStringArg(request, "key")
+	maxBytes := getIntArg(request, "max_bytes")
+	if maxBytes <= 0 {
+		maxBytes = defaultObjectTextBytes
+	}
+	if maxBytes > maxObjectTextBytes {
+		return mcp.NewToolResultError(fmt.Sprintf("max_bytes must be at most %d", maxObjectTextBytes)), nil
+	}
+
+	client, bucket, result := h.bucketForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	object, err := client.HeadObject(bucket, key)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if !isTextContentType(object.ContentType) && !isGenericContentType(object.ContentType) {
+		return mcp.NewToolResultError(fmt.Sprintf("Object %s has content type %s, only text objects can be read",
+			key, object.ContentType)), nil
+	}
+
+	data, truncated, err := client.GetObject(bucket, key, int64(maxBytes))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if truncated {
+		// Do not cut a multi-byte character in half at the size cap
+		for i := 0; i < utf8.UTFMax && len(data) > 0 && !utf8.Valid(data); i++ {
+			data = data[:len(data)-1]
+		}
+	}
+	if !utf8.Valid(data) || strings.ContainsRune(string(data), 0) {
+		return mcp.NewToolResultError(fmt.Sprintf("Object %s is not UTF-8 text", key)), nil
+	}
+
+	return jsonResult(map[string]interface{}{
+		"bucket":       bucket.BucketName,
+		"key":          key,
+		"size":         object.Size,
+		"content_type": object.ContentType,
+		"truncated":    truncated,
+		"text":         string(data),
+	})
+}
+
+// GeneratePresignedURLToolHandler handles presigned URL requests
+func (h *Handlers) GeneratePresignedURLToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	key := getStringArg(request, "key")
+	expiresIn := getIntArg(request, "expires_in")
+	if expiresIn <= 0 {
+		expiresIn = defaultPresignExpiry
+	}
+	if expiresIn > maxPresignExpiry {
+		return mcp.NewToolResultError(fmt.Sprintf("expires_in must be at most %d seconds", maxPresignExpiry)), nil
+	}
+
+	client, bucket, result := h.bucketForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	// Fail early rather than hand out a URL to a missing object
+	if _, err := client.HeadObject(bucket, key); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: []target{{ID: bucket.BucketName, Tag: bucket.Tag}},
+		Preview: map[string]interface{}{"bucket": bucket.BucketName, "key": key, "expires_in": expiresIn},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			presignedURL, expires, err := client.PresignObjectURL(bucket, key, time.Duration(expiresIn)*time.Second)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			log.Printf("Generated presigned URL for %s/%s expiring at %s", bucket.BucketName, key, expires.Format(time.RFC3339))
+
+			return jsonResult(map[string]interface{}{
+				"bucket":     bucket.BucketName,
+				"key":        key,
+				"url":        presignedURL,
+				"expires_at": expires.Format(time.RFC3339),
+			})
+		},
+	})
+}
+
+// bucketForRequest returns the client and the bucket named by the bucket argument,
+// or an error result if the bucket cannot be found or the caller may not see it
+func (h *Handlers) bucketForRequest(ctx context.Context, request mcp.CallToolRequest) (*ucloud.UCloudClient, *ufile.UFileBucketSet, *mcp.CallToolResult) {
+	bucketName := getStringArg(request, "bucket")
+
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(err.Error())
+	}
+
+	bucket, err := client.DescribeBucket(bucketName)
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(err.Error())
+	}
+	if !isTagVisible(ctx, client, bucket.Tag) {
+		return nil, nil, mcp.NewToolResultError(fmt.Sprintf("permission denied: bucket %s is outside the tags allowed for %s",
+			bucket.BucketName, IdentityFromContext(ctx)))
+	}
+	return client, bucket, nil
+}
+
+// isTextContentType reports whether a content type denotes text
+func isTextContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
+		return true
+	}
+	for _, t := range textContentTypes {
+		if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// isGenericContentType reports whether a content type says nothing about the
+// content, as for objects uploaded without one. Such objects are checked for
+// valid UTF-8 instead.
+func isGenericContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return contentType == "" || err != nil || mediaType == "application/octet-stream"
+}
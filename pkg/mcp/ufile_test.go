@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+)
+
+func TestObjectToolsDryRun(t *testing.T) {
+	api := &fakeAPI{responses: make(map[string]string)}
+
+	// Object requests go to the bucket's domain over HTTPS, served by the same fake
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.Header().Set("Content-Length", "5")
+			return
+		}
+		api.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	domain := strings.TrimPrefix(server.URL, "https://")
+	api.responses["DescribeBucket"] = `{"RetCode":0,"DataSet":[{"BucketName":"logs","Tag":"Default","Domain":{"Src":["` + domain + `"]}}]}`
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caBundle, certificate, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	h := newTestHandlers(t, &config.Config{
+		Profile: config.Profile{
+			Region:     "cn-bj2",
+			ProjectID:  "org-test",
+			PublicKey:  "public",
+			PrivateKey: "private",
+			BaseURL:    server.URL,
+		},
+		HTTP: config.HTTPConfig{CABundle: caBundle},
+	})
+
+	// Presigning only signs locally, so the dry run plans no API requests
+	checkDryRun(t, h, api, dryRunCase{
+		tool:    "generate_presigned_url",
+		handler: (*Handlers).GeneratePresignedURLToolHandler,
+		args:    map[string]interface{}{"bucket": "logs", "key": "app.log"},
+	})
+}
@@ -0,0 +1,294 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+	"github.com/ucloud/ucloud-sdk-go/services/uhost"
+	"github.com/ucloud/ucloud-sdk-go/services/uk8s"
+)
+
+// registerK8STools registers the UK8S tools
+func (s *MCPServer) registerK8STools() {
+	listClustersTool := mcp.NewTool("list_k8s_clusters",
+		mcp.WithDescription("List UCloud UK8S Kubernetes clusters"),
+		withProfile(),
+		withProjectID(),
+		withAllProjects(),
+	)
+	s.addTool(listClustersTool, s.handlers.ListK8SClustersToolHandler, readOnlyTool)
+
+	describeClusterTool := mcp.NewTool("describe_k8s_cluster",
+		mcp.WithDescription("Get a UK8S cluster with its version, node groups and the instance IDs of its masters and nodes"),
+		mcp.WithString("cluster_id",
+			mcp.Required(),
+			mcp.Description("ID of the cluster to describe"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(describeClusterTool, s.handlers.DescribeK8SClusterToolHandler, readOnlyTool)
+
+	listNodesTool := mcp.NewTool("list_k8s_nodes",
+		mcp.WithDescription("List the nodes of a UK8S cluster with the instances backing them. "+
+			"Use the instance IDs with the instance tools to investigate a node."),
+		mcp.WithString("cluster_id",
+			mcp.Required(),
+			mcp.Description("ID of the cluster"),
+		),
+		mcp.WithString("role",
+			mcp.Description("Only list nodes with this role"),
+			mcp.Enum("master", "node"),
+		),
+		mcp.WithBoolean("include_metrics",
+			mcp.Description("Include the monitoring metrics of each node's instance"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(listNodesTool, s.handlers.ListK8SNodesToolHandler, readOnlyTool)
+}
+
+// ListK8SClustersToolHandler handles k8s cluster list tool requests
+func (h *Handlers) ListK8SClustersToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	clients, err := h.clientsForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var allClusters []interface{}
+	for _, client := range clients {
+		clusters, err := visibleK8SClusters(ctx, client)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list k8s clusters in project %s: %v", client.ProjectID(), err)), nil
+		}
+
+		for i := range clusters {
+			info := ucloud.FormatK8SClusterInfo(&clusters[i])
+			info.ProjectID = client.ProjectID()
+			allClusters = append(allClusters, info)
+		}
+	}
+
+	log.Printf("Total k8s clusters found: %d", len(allClusters))
+
+	jsonData, err := json.MarshalIndent(allClusters, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal k8s cluster data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// DescribeK8SClusterToolHandler handles k8s cluster description requests
+func (h *Handlers) DescribeK8SClusterToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, cluster, nodes, _, result := h.k8sClusterForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	groups, err := client.ListK8SNodeGroups(cluster.ClusterId)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	masterIDs := []string{}
+	nodeIDs := []string{}
+	visible := make(map[string]bool)
+	for _, node := range nodes {
+		visible[node.NodeId] = true
+		visible[node.InstanceId] = true
+		if node.NodeRole == ucloud.K8SNodeRoleMaster {
+			masterIDs = append(masterIDs, node.InstanceId)
+		} else {
+			nodeIDs = append(nodeIDs, node.InstanceId)
+		}
+	}
+
+	nodeGroups := make([]*ucloud.K8SNodeGroupInfo, 0, len(groups))
+	for i := range groups {
+		group := ucloud.FormatK8SNodeGroupInfo(&groups[i])
+		members := []string{}
+		for _, id := range group.NodeIDs {
+			if visible[id] {
+				members = append(members, id)
+			}
+		}
+		group.NodeIDs = members
+		nodeGroups = append(nodeGroups, group)
+	}
+
+	info := ucloud.FormatK8SClusterDetail(cluster)
+	info.ProjectID = client.ProjectID()
+	response := struct {
+		*ucloud.K8SClusterInfo
+		NodeGroups        []*ucloud.K8SNodeGroupInfo `json:"node_groups"`
+		MasterInstanceIDs []string                   `json:"master_instance_ids"`
+		NodeInstanceIDs   []string                   `json:"node_instance_ids"`
+	}{info, nodeGroups, masterIDs, nodeIDs}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal k8s cluster info: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// ListK8SNodesToolHandler handles k8s node list requests
+func (h *Handlers) ListK8SNodesToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	role := getStringArg(request, "role")
+	includeMetrics := getBoolArg(request, "include_metrics")
+
+	client, cluster, nodes, instances, result := h.k8sClusterForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	groups, err := client.ListK8SNodeGroups(cluster.ClusterId)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	groupOf := make(map[string]string)
+	for _, group := range groups {
+		for _, id := range group.NodeList {
+			groupOf[id] = group.NodeGroupId
+		}
+	}
+
+	// Fetch the metrics of all nodes at once, each lookup pages through every instance
+	var metrics map[string][]ucloud.InstanceMetrics
+	if includeMetrics {
+		var nodeInstances []*uhost.UHostInstanceSet
+		for _, node := range nodes {
+			if instance, ok := instances[node.InstanceId]; ok && (role == "" || node.NodeRole == role) {
+				nodeInstances = append(nodeInstances, instance)
+			}
+		}
+		metrics, err = client.GetInstancesMetrics(nodeInstances)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get metrics of k8s cluster %s: %v", cluster.ClusterId, err)), nil
+		}
+	}
+
+	allNodes := []*ucloud.K8SNodeInfo{}
+	for i := range nodes {
+		node := &nodes[i]
+		if role != "" && node.NodeRole != role {
+			continue
+		}
+
+		info := ucloud.FormatK8SNodeInfo(node)
+		info.NodeGroupID = groupOf[node.NodeId]
+		if info.NodeGroupID == "" {
+			info.NodeGroupID = groupOf[node.InstanceId]
+		}
+
+		instance, ok := instances[node.InstanceId]
+		if ok {
+			info.InstanceState = instance.State
+		}
+		if includeMetrics && ok {
+			info.Metrics = metrics[node.InstanceId]
+		}
+		allNodes = append(allNodes, info)
+	}
+
+	log.Printf("Total k8s nodes found: %d", len(allNodes))
+
+	jsonData, err := json.MarshalIndent(allNodes, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal k8s node data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// k8sClusterForRequest returns the client, the cluster named by the cluster_id argument,
+// the nodes of the cluster the caller may see and the instances backing them, or an error
+// result if the cluster cannot be found or the caller may see none of its nodes
+func (h *Handlers) k8sClusterForRequest(ctx context.Context, request mcp.CallToolRequest) (*ucloud.UCloudClient, *uk8s.DescribeUK8SClusterResponse, []uk8s.NodeInfoV2, map[string]*uhost.UHostInstanceSet, *mcp.CallToolResult) {
+	clusterID := getStringArg(request, "cluster_id")
+
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return nil, nil, nil, nil, mcp.NewToolResultError(err.Error())
+	}
+
+	// List the instances once, for the scope check and to link nodes to their instances
+	all, err := client.ListInstances()
+	if err != nil {
+		return nil, nil, nil, nil, mcp.NewToolResultError(err.Error())
+	}
+
+	cluster, nodes, err := client.DescribeK8SCluster(clusterID, all)
+	if err != nil {
+		return nil, nil, nil, nil, mcp.NewToolResultError(err.Error())
+	}
+
+	instances := visibleInstances(ctx, client, all)
+	if grantFromContext(ctx) == nil {
+		// Unrestricted callers see every node
+		return client, cluster, nodes, instances, nil
+	}
+
+	nodes = visibleK8SNodes(nodes, instances)
+	if len(nodes) == 0 {
+		return nil, nil, nil, nil, mcp.NewToolResultError(fmt.Sprintf("permission denied: no node of k8s cluster %s is within the tags allowed for %s",
+			cluster.ClusterId, IdentityFromContext(ctx)))
+	}
+	return client, cluster, nodes, instances, nil
+}
+
+// visibleK8SClusters returns the clusters the caller may see: for callers restricted by
+// roles, those with a node the caller may see. The instances of the project are
+// listed at most once.
+func visibleK8SClusters(ctx context.Context, client *ucloud.UCloudClient) ([]uk8s.ClusterSet, error) {
+	if grantFromContext(ctx) == nil {
+		return client.ListK8SClusters()
+	}
+
+	all, err := client.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := client.ListK8SClustersWithNodes(all)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := visibleInstances(ctx, client, all)
+	var visible []uk8s.ClusterSet
+	for _, cluster := range clusters {
+		if len(visibleK8SNodes(cluster.Nodes, instances)) > 0 {
+			visible = append(visible, cluster.ClusterSet)
+		}
+	}
+	return visible, nil
+}
+
+// visibleInstances returns the instances the caller's roles allow it to see, by ID
+func visibleInstances(ctx context.Context, client *ucloud.UCloudClient, instances []uhost.UHostInstanceSet) map[string]*uhost.UHostInstanceSet {
+	visible := make(map[string]*uhost.UHostInstanceSet)
+	for i := range instances {
+		if isInstanceVisible(ctx, client, &instances[i]) {
+			visible[instances[i].UHostId] = &instances[i]
+		}
+	}
+	return visible
+}
+
+// visibleK8SNodes returns the nodes backed by the given instances
+func visibleK8SNodes(nodes []uk8s.NodeInfoV2, instances map[string]*uhost.UHostInstanceSet) []uk8s.NodeInfoV2 {
+	var visible []uk8s.NodeInfoV2
+	for _, node := range nodes {
+		if _, ok := instances[node.InstanceId]; ok {
+			visible = append(visible, node)
+		}
+	}
+	return visible
+}
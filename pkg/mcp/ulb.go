@@ -0,0 +1,485 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+	"github.com/ucloud/ucloud-sdk-go/services/ulb"
+)
+
+// registerLoadBalancerTools registers the ULB tools
+func (s *MCPServer) registerLoadBalancerTools() {
+	listLoadBalancersTool := mcp.NewTool("list_load_balancers",
+		mcp.WithDescription("List UCloud load balancers (ULB) with their VServers and backends"),
+		withProfile(),
+		withProjectID(),
+		withAllProjects(),
+	)
+	s.addTool(listLoadBalancersTool, s.handlers.ListLoadBalancersToolHandler, readOnlyTool)
+
+	describeLoadBalancerTool := mcp.NewTool("describe_load_balancer",
+		mcp.WithDescription("Get a load balancer with its VServers (listeners) and backends with their health status"),
+		mcp.WithString("load_balancer_id",
+			mcp.Required(),
+			mcp.Description("ID of the load balancer to describe"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(describeLoadBalancerTool, s.handlers.DescribeLoadBalancerToolHandler, readOnlyTool)
+
+	addBackendTool := mcp.NewTool("add_backend",
+		mcp.WithDescription("Add an instance as a backend of a load balancer VServer"),
+		mcp.WithString("load_balancer_id",
+			mcp.Required(),
+			mcp.Description("ID of the load balancer"),
+		),
+		mcp.WithString("vserver_id",
+			mcp.Required(),
+			mcp.Description("ID of the VServer to add the backend to"),
+		),
+		mcp.WithString("instance_id",
+			mcp.Required(),
+			mcp.Description("ID of the instance to add"),
+		),
+		mcp.WithNumber("port",
+			mcp.Required(),
+			mcp.Description("Port the instance serves on"),
+			mcp.Min(1),
+			mcp.Max(65535),
+		),
+		mcp.WithNumber("weight",
+			mcp.Description("Weight of the backend for weighted round robin"),
+			mcp.DefaultNumber(1),
+			mcp.Min(1),
+			mcp.Max(100),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(addBackendTool, s.handlers.AddBackendToolHandler, mutatingTool)
+
+	removeBackendTool := mcp.NewTool("remove_backend",
+		mcp.WithDescription("Remove backends from a load balancer, e.g. to drain an instance before rebooting it"),
+		mcp.WithString("load_balancer_id",
+			mcp.Required(),
+			mcp.Description("ID of the load balancer"),
+		),
+		mcp.WithString("backend_id",
+			mcp.Description("ID of the backend to remove"),
+		),
+		mcp.WithString("instance_id",
+			mcp.Description("Remove every backend of this instance, instead of backend_id"),
+		),
+		mcp.WithString("vserver_id",
+			mcp.Description("Only remove backends of this VServer"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(removeBackendTool, s.handlers.RemoveBackendToolHandler, mutatingTool)
+
+	setWeightTool := mcp.NewTool("set_backend_weight",
+		mcp.WithDescription("Change the weight of load balancer backends"),
+		mcp.WithString("load_balancer_id",
+			mcp.Required(),
+			mcp.Description("ID of the load balancer"),
+		),
+		mcp.WithNumber("weight",
+			mcp.Required(),
+			mcp.Description("New weight of the backends"),
+			mcp.Min(1),
+			mcp.Max(100),
+		),
+		mcp.WithString("backend_id",
+			mcp.Description("ID of the backend to change"),
+		),
+		mcp.WithString("instance_id",
+			mcp.Description("Change every backend of this instance, instead of backend_id"),
+		),
+		mcp.WithString("vserver_id",
+			mcp.Description("Only change backends of this VServer"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(setWeightTool, s.handlers.SetBackendWeightToolHandler, mutatingTool)
+
+	setEnabledTool := mcp.NewTool("set_backend_enabled",
+		mcp.WithDescription("Enable or disable load balancer backends. A disabled backend stays attached but receives no traffic, e.g. to drain an instance before rebooting it"),
+		mcp.WithString("load_balancer_id",
+			mcp.Required(),
+			mcp.Description("ID of the load balancer"),
+		),
+		mcp.WithBoolean("enabled",
+			mcp.Required(),
+			mcp.Description("Whether the backends receive traffic"),
+		),
+		mcp.WithString("backend_id",
+			mcp.Description("ID of the backend to change"),
+		),
+		mcp.WithString("instance_id",
+			mcp.Description("Change every backend of this instance, instead of backend_id"),
+		),
+		mcp.WithString("vserver_id",
+			mcp.Description("Only change backends of this VServer"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(setEnabledTool, s.handlers.SetBackendEnabledToolHandler, mutatingTool)
+}
+
+// ListLoadBalancersToolHandler handles load balancer list tool requests
+func (h *Handlers) ListLoadBalancersToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	clients, err := h.clientsForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var allLoadBalancers []interface{}
+	for _, client := range clients {
+		loadBalancers, err := client.ListLoadBalancers()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list load balancers in project %s: %v", client.ProjectID(), err)), nil
+		}
+		visible, err := visibleBackendHosts(ctx, client)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		for _, lb := range loadBalancers {
+			if !isTagVisible(ctx, client, lb.Tag) {
+				continue
+			}
+			lbCopy := lb // Create a copy to avoid using loop variable reference
+			info := ucloud.FormatLoadBalancerInfo(&lbCopy)
+			info.ProjectID = client.ProjectID()
+			hideBackends(info, visible)
+			allLoadBalancers = append(allLoadBalancers, info)
+		}
+	}
+
+	log.Printf("Total load balancers found: %d", len(allLoadBalancers))
+
+	jsonData, err := json.MarshalIndent(allLoadBalancers, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal load balancer data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// DescribeLoadBalancerToolHandler handles load balancer description requests
+func (h *Handlers) DescribeLoadBalancerToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, lb, result := h.loadBalancerForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	visible, err := visibleBackendHosts(ctx, client)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	info := ucloud.FormatLoadBalancerInfo(lb)
+	info.ProjectID = client.ProjectID()
+	hideBackends(info, visible)
+	jsonData, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal load balancer info: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// AddBackendToolHandler handles requests to add a backend to a load balancer
+func (h *Handlers) AddBackendToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	vserverID := getStringArg(request, "vserver_id")
+	instanceID := getStringArg(request, "instance_id")
+	port := getIntArg(request, "port")
+	if port < 1 || port > 65535 {
+		return mcp.NewToolResultError("port must be between 1 and 65535"), nil
+	}
+	weight := getIntArg(request, "weight")
+	if weight == 0 {
+		weight = 1
+	}
+
+	client, lb, result := h.loadBalancerForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	found := false
+	for _, vserver := range lb.VServerSet {
+		if vserver.VServerId != vserverID {
+			continue
+		}
+		found = true
+		for _, backend := range vserver.BackendSet {
+			if backend.ResourceId == instanceID && backend.Port == port {
+				return mcp.NewToolResultError(fmt.Sprintf("Instance %s is already backend %s of VServer %s on port %d",
+					instanceID, backend.BackendId, vserverID, port)), nil
+			}
+		}
+	}
+	if !found {
+		return mcp.NewToolResultError(fmt.Sprintf("VServer %s not found in load balancer %s", vserverID, lb.ULBId)), nil
+	}
+
+	instance, err := client.DescribeInstance(instanceID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to describe instance %v: %v", instanceID, err)), nil
+	}
+	if err := checkInstanceTag(ctx, client, instance); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client: client,
+		Targets: []target{
+			{ID: lb.ULBId, Tag: lb.Tag},
+			{ID: instance.UHostId, Tag: instance.Tag},
+		},
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			backendID, err := client.AddBackend(lb.ULBId, vserverID, instance.UHostId, port, weight)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(map[string]interface{}{
+				"backend_id":  backendID,
+				"vserver_id":  vserverID,
+				"instance_id": instance.UHostId,
+				"port":        port,
+				"weight":      weight,
+			})
+		},
+	})
+}
+
+// RemoveBackendToolHandler handles requests to remove backends from a load balancer
+func (h *Handlers) RemoveBackendToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, lb, result := h.loadBalancerForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	backends, targets, result := h.backendsForRequest(ctx, request, client, lb)
+	if result != nil {
+		return result, nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: targets,
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			removed := make([]string, 0, len(backends))
+			for _, backend := range backends {
+				if err := client.RemoveBackend(lb.ULBId, backend.Backend.ID); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("%v (already removed: %v)", err, removed)), nil
+				}
+				removed = append(removed, backend.Backend.ID)
+			}
+			return jsonResult(map[string]interface{}{
+				"load_balancer_id": lb.ULBId,
+				"removed":          backends,
+			})
+		},
+	})
+}
+
+// SetBackendWeightToolHandler handles requests to change the weight of load balancer backends
+func (h *Handlers) SetBackendWeightToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	weight := getIntArg(request, "weight")
+	if weight < 1 || weight > 100 {
+		return mcp.NewToolResultError("weight must be between 1 and 100"), nil
+	}
+
+	client, lb, result := h.loadBalancerForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	backends, targets, result := h.backendsForRequest(ctx, request, client, lb)
+	if result != nil {
+		return result, nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: targets,
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			changed := make([]string, 0, len(backends))
+			for _, backend := range backends {
+				if err := client.SetBackendWeight(lb.ULBId, backend.Backend.ID, weight); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("%v (already changed: %v)", err, changed)), nil
+				}
+				changed = append(changed, backend.Backend.ID)
+			}
+			return jsonResult(map[string]interface{}{
+				"load_balancer_id": lb.ULBId,
+				"backends":         backends,
+				"weight":           weight,
+			})
+		},
+	})
+}
+
+// SetBackendEnabledToolHandler handles requests to enable or disable load balancer backends
+func (h *Handlers) SetBackendEnabledToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	enabled, ok := request.Params.Arguments["enabled"].(bool)
+	if !ok {
+		return mcp.NewToolResultError("enabled is required"), nil
+	}
+
+	client, lb, result := h.loadBalancerForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	backends, targets, result := h.backendsForRequest(ctx, request, client, lb)
+	if result != nil {
+		return result, nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:  client,
+		Targets: targets,
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			changed := make([]string, 0, len(backends))
+			for _, backend := range backends {
+				if err := client.SetBackendEnabled(lb.ULBId, backend.Backend.ID, enabled); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("%v (already changed: %v)", err, changed)), nil
+				}
+				changed = append(changed, backend.Backend.ID)
+			}
+			return jsonResult(map[string]interface{}{
+				"load_balancer_id": lb.ULBId,
+				"backends":         backends,
+				"enabled":          enabled,
+			})
+		},
+	})
+}
+
+// vserverBackend is a backend together with the VServer it belongs to
+type vserverBackend struct {
+	VServerID string             `json:"vserver_id"`
+	Backend   ucloud.BackendInfo `json:"backend"`
+}
+
+// backendsForRequest returns the backends selected by the backend_id or instance_id
+// and vserver_id arguments, with the targets of a change to them. The caller must be
+// allowed to see the instances behind the backends.
+func (h *Handlers) backendsForRequest(ctx context.Context, request mcp.CallToolRequest, client *ucloud.UCloudClient, lb *ulb.ULBSet) ([]vserverBackend, []target, *mcp.CallToolResult) {
+	backendID := getStringArg(request, "backend_id")
+	instanceID := getStringArg(request, "instance_id")
+	vserverID := getStringArg(request, "vserver_id")
+	if (backendID == "") == (instanceID == "") {
+		return nil, nil, mcp.NewToolResultError("exactly one of backend_id and instance_id is required")
+	}
+
+	var backends []vserverBackend
+	for _, vserver := range lb.VServerSet {
+		if vserverID != "" && vserver.VServerId != vserverID {
+			continue
+		}
+		for i := range vserver.BackendSet {
+			backend := &vserver.BackendSet[i]
+			if backend.BackendId == backendID || (instanceID != "" && backend.ResourceId == instanceID) {
+				backends = append(backends, vserverBackend{VServerID: vserver.VServerId, Backend: ucloud.FormatBackendInfo(backend)})
+			}
+		}
+	}
+	if len(backends) == 0 {
+		return nil, nil, mcp.NewToolResultError(fmt.Sprintf("No matching backend found in load balancer %s", lb.ULBId))
+	}
+
+	targets := []target{{ID: lb.ULBId, Tag: lb.Tag}}
+	checked := make(map[string]bool)
+	for _, backend := range backends {
+		resourceID := backend.Backend.ResourceID
+		if backend.Backend.ResourceType != ucloud.BackendTypeUHost || checked[resourceID] {
+			continue
+		}
+		checked[resourceID] = true
+
+		instance, err := client.DescribeInstance(resourceID)
+		if err != nil {
+			return nil, nil, mcp.NewToolResultError(fmt.Sprintf("Failed to describe instance %v: %v", resourceID, err))
+		}
+		if err := checkInstanceTag(ctx, client, instance); err != nil {
+			return nil, nil, mcp.NewToolResultError(err.Error())
+		}
+		targets = append(targets, target{ID: instance.UHostId, Tag: instance.Tag})
+	}
+	return backends, targets, nil
+}
+
+// visibleBackendHosts returns the IDs of the instances whose backends the caller may see,
+// or nil when neither the scope nor the caller's roles hide any
+func visibleBackendHosts(ctx context.Context, client *ucloud.UCloudClient) (map[string]bool, error) {
+	if !isRestricted(ctx, client) {
+		return nil, nil
+	}
+
+	// ListInstances only returns instances in scope
+	instances, err := client.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+	visible := make(map[string]bool)
+	for i := range instances {
+		if isInstanceVisible(ctx, client, &instances[i]) {
+			visible[instances[i].UHostId] = true
+		}
+	}
+	return visible, nil
+}
+
+// hideBackends removes the backends the caller may not see from a load balancer and
+// counts them per VServer instead. Backends other than instances are hidden from
+// restricted callers, as their scope is unknown.
+func hideBackends(info *ucloud.LoadBalancerInfo, visible map[string]bool) {
+	if visible == nil {
+		return
+	}
+	for i := range info.VServers {
+		vserver := &info.VServers[i]
+		backends := vserver.Backends[:0]
+		for _, backend := range vserver.Backends {
+			if backend.ResourceType == ucloud.BackendTypeUHost && visible[backend.ResourceID] {
+				backends = append(backends, backend)
+			} else {
+				vserver.HiddenBackends++
+			}
+		}
+		vserver.Backends = backends
+	}
+}
+
+// loadBalancerForRequest returns the client and the load balancer named by the load_balancer_id
+// argument, or an error result if the load balancer cannot be found or the caller may not see it
+func (h *Handlers) loadBalancerForRequest(ctx context.Context, request mcp.CallToolRequest) (*ucloud.UCloudClient, *ulb.ULBSet, *mcp.CallToolResult) {
+	lbID := getStringArg(request, "load_balancer_id")
+
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(err.Error())
+	}
+
+	lb, err := client.DescribeLoadBalancer(lbID)
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(fmt.Sprintf("Failed to describe load balancer %v: %v", lbID, err))
+	}
+	if !isTagVisible(ctx, client, lb.Tag) {
+		return nil, nil, mcp.NewToolResultError(fmt.Sprintf("permission denied: load balancer %s is outside the tags allowed for %s",
+			lb.ULBId, IdentityFromContext(ctx)))
+	}
+	return client, lb, nil
+}
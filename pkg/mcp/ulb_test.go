@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+)
+
+const testLoadBalancer = `{"ULBId":"ulb-test","Name":"web","Tag":"Default","VServerSet":[{"VServerId":"vserver-test","BackendSet":[` +
+	`{"BackendId":"backend-test","ResourceId":"uhost-test","ResourceType":"UHost","Port":80,"Weight":1,"Enabled":1}]}]}`
+
+func TestLoadBalancerToolsDryRun(t *testing.T) {
+	responses := map[string]string{
+		"DescribeULB":           `{"RetCode":0,"TotalCount":1,"DataSet":[` + testLoadBalancer + `]}`,
+		"DescribeUHostInstance": `{"RetCode":0,"TotalCount":1,"UHostSet":[` + testInstance + `]}`,
+	}
+	other := `{"RetCode":0,"TotalCount":1,"UHostSet":[{"UHostId":"uhost-other","Zone":"cn-bj2-02","State":"Running","Tag":"Default"}]}`
+
+	testDryRuns(t, responses, []dryRunCase{
+		{
+			tool:      "add_backend",
+			handler:   (*Handlers).AddBackendToolHandler,
+			args:      map[string]interface{}{"load_balancer_id": "ulb-test", "vserver_id": "vserver-test", "instance_id": "uhost-other", "port": float64(80)},
+			responses: map[string]string{"DescribeUHostInstance": other},
+			want:      []string{"AllocateBackend"},
+		},
+		{
+			tool:    "remove_backend",
+			handler: (*Handlers).RemoveBackendToolHandler,
+			args:    map[string]interface{}{"load_balancer_id": "ulb-test", "backend_id": "backend-test"},
+			want:    []string{"ReleaseBackend"},
+		},
+		{
+			tool:    "set_backend_weight",
+			handler: (*Handlers).SetBackendWeightToolHandler,
+			args:    map[string]interface{}{"load_balancer_id": "ulb-test", "instance_id": "uhost-test", "weight": float64(10)},
+			want:    []string{"UpdateBackendAttribute"},
+		},
+		{
+			tool:    "set_backend_enabled",
+			handler: (*Handlers).SetBackendEnabledToolHandler,
+			args:    map[string]interface{}{"load_balancer_id": "ulb-test", "instance_id": "uhost-test", "enabled": false},
+			want:    []string{"UpdateBackendAttribute"},
+		},
+	})
+}
+
+func TestHideBackends(t *testing.T) {
+	backend := func(id, resourceType, resourceID string) ucloud.BackendInfo {
+		return ucloud.BackendInfo{ID: id, ResourceType: resourceType, ResourceID: resourceID}
+	}
+	newInfo := func() *ucloud.LoadBalancerInfo {
+		return &ucloud.LoadBalancerInfo{VServers: []ucloud.VServerInfo{{
+			ID: "vserver-test",
+			Backends: []ucloud.BackendInfo{
+				backend("backend-visible", ucloud.BackendTypeUHost, "uhost-visible"),
+				backend("backend-hidden", ucloud.BackendTypeUHost, "uhost-hidden"),
+				backend("backend-uni", "UNI", "uni-test"),
+			},
+		}}}
+	}
+
+	tests := []struct {
+		name       string
+		visible    map[string]bool
+		wantIDs    []string
+		wantHidden int
+	}{
+		{"unrestricted", nil, []string{"backend-visible", "backend-hidden", "backend-uni"}, 0},
+		{"restricted", map[string]bool{"uhost-visible": true}, []string{"backend-visible"}, 2},
+		{"nothing visible", map[string]bool{}, nil, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := newInfo()
+			hideBackends(info, tt.visible)
+
+			vserver := info.VServers[0]
+			var ids []string
+			for _, backend := range vserver.Backends {
+				ids = append(ids, backend.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || vserver.HiddenBackends != tt.wantHidden {
+				t.Errorf("backends = %v with %d hidden, want %v with %d hidden", ids, vserver.HiddenBackends,
+					tt.wantIDs, tt.wantHidden)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+)
+
+// registerCacheTools registers the UMem tools
+func (s *MCPServer) registerCacheTools() {
+	listCachesTool := mcp.NewTool("list_cache_instances",
+		mcp.WithDescription("List UCloud Redis and Memcache instances (UMem) with their memory usage"),
+		withProfile(),
+		withProjectID(),
+		withAllProjects(),
+	)
+	s.addTool(listCachesTool, s.handlers.ListCachesToolHandler, readOnlyTool)
+
+	describeCacheTool := mcp.NewTool("describe_cache_instance",
+		mcp.WithDescription("Get a Redis or Memcache instance with its memory usage, version, high availability type and connection address"),
+		mcp.WithString("cache_id",
+			mcp.Required(),
+			mcp.Description("ID of the cache instance to describe"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(describeCacheTool, s.handlers.DescribeCacheToolHandler, readOnlyTool)
+
+	metricsTool := mcp.NewTool("get_cache_metrics",
+		mcp.WithDescription("Get monitoring metrics for a Redis or Memcache instance, e.g. to diagnose evictions"),
+		mcp.WithString("cache_id",
+			mcp.Required(),
+			mcp.Description("ID of the cache instance"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(metricsTool, s.handlers.GetCacheMetricsToolHandler, readOnlyTool)
+
+	resizeCacheTool := mcp.NewTool("resize_cache_instance",
+		mcp.WithDescription("Grow the memory of a Redis instance to 1, 2, 4, 8, 16 or 32 GB. Instances cannot shrink. Connections may be interrupted briefly."),
+		mcp.WithString("cache_id",
+			mcp.Required(),
+			mcp.Description("ID of the Redis instance to resize"),
+		),
+		mcp.WithNumber("size",
+			mcp.Required(),
+			mcp.Description("New memory size in GB: 1, 2, 4, 8, 16 or 32, larger than the current size"),
+			mcp.Min(1),
+			mcp.Max(32),
+		),
+		withConfirm(),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(resizeCacheTool, s.handlers.ResizeCacheToolHandler, mutatingTool)
+}
+
+// ListCachesToolHandler handles cache instance list tool requests
+func (h *Handlers) ListCachesToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	clients, err := h.clientsForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var allCaches []interface{}
+	for _, client := range clients {
+		caches, err := client.ListCaches()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list cache instances in project %s: %v", client.ProjectID(), err)), nil
+		}
+
+		for _, cache := range caches {
+			if !isTagVisible(ctx, client, cache.Tag) {
+				continue
+			}
+			cache.ProjectID = client.ProjectID()
+			allCaches = append(allCaches, cache)
+		}
+	}
+
+	log.Printf("Total cache instances found: %d", len(allCaches))
+
+	jsonData, err := json.MarshalIndent(allCaches, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal cache instance data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// DescribeCacheToolHandler handles cache instance description requests
+func (h *Handlers) DescribeCacheToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, cache, result := h.cacheForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+	cache.ProjectID = client.ProjectID()
+
+	jsonData, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal cache instance info: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// GetCacheMetricsToolHandler handles cache instance metrics retrieval requests
+func (h *Handlers) GetCacheMetricsToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, cache, result := h.cacheForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+
+	metrics, err := client.GetCacheMetrics(cache)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get metrics: %v", err)), nil
+	}
+	if metrics == nil {
+		return mcp.NewToolResultError("Cache instance metrics not found"), nil
+	}
+
+	return jsonResult(map[string]interface{}{
+		"cache_id":   cache.ID,
+		"project_id": client.ProjectID(),
+		"name":       cache.Name,
+		"status":     cache.Status,
+		"basic_info": map[string]interface{}{
+			"engine":       cache.Engine,
+			"version":      cache.Version,
+			"size":         cache.Size,
+			"used_mb":      cache.UsedMB,
+			"memory_usage": cache.MemoryUsage,
+			"address":      cache.Address,
+		},
+		"metrics":   metrics,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// ResizeCacheToolHandler handles cache instance resize requests
+func (h *Handlers) ResizeCacheToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	size := getIntArg(request, "size")
+
+	client, cache, result := h.cacheForRequest(ctx, request)
+	if result != nil {
+		return result, nil
+	}
+	if cache.Engine != ucloud.CacheEngineRedis {
+		return mcp.NewToolResultError(fmt.Sprintf("Cache instance %s is a %s instance, only redis instances can be resized",
+			cache.ID, cache.Engine)), nil
+	}
+	if err := ucloud.CheckRedisResize(cache, size); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return h.applyChange(ctx, request, change{
+		Client:     client,
+		Targets:    []target{{ID: cache.ID, Tag: cache.Tag}},
+		Disruption: fmt.Sprintf("Resizing %s from %d GB to %d GB may briefly interrupt connections to it", cache.ID, cache.Size, size),
+		Run: func(client *ucloud.UCloudClient) (*mcp.CallToolResult, error) {
+			if err := client.ResizeCache(cache, size); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(map[string]interface{}{
+				"cache_id":      cache.ID,
+				"previous_size": cache.Size,
+				"size":          size,
+			})
+		},
+	})
+}
+
+// cacheForRequest returns the client and the cache instance named by the cache_id argument,
+// or an error result if the instance cannot be found or the caller may not see it
+func (h *Handlers) cacheForRequest(ctx context.Context, request mcp.CallToolRequest) (*ucloud.UCloudClient, *ucloud.CacheInfo, *mcp.CallToolResult) {
+	cacheID := getStringArg(request, "cache_id")
+
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(err.Error())
+	}
+
+	cache, err := client.DescribeCache(cacheID)
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(err.Error())
+	}
+	if !isTagVisible(ctx, client, cache.Tag) {
+		return nil, nil, mcp.NewToolResultError(fmt.Sprintf("permission denied: cache instance %s is outside the tags allowed for %s",
+			cache.ID, IdentityFromContext(ctx)))
+	}
+	return client, cache, nil
+}
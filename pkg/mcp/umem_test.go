@@ -0,0 +1,20 @@
+package mcp
+
+import "testing"
+
+const testRedis = `{"GroupId":"uredis-test","Name":"sessions","Zone":"cn-bj2-02","State":"Running","Size":1,"Type":"single","Tag":"Default"}`
+
+func TestCacheToolsDryRun(t *testing.T) {
+	responses := map[string]string{
+		"DescribeURedisGroup": `{"RetCode":0,"TotalCount":1,"DataSet":[` + testRedis + `]}`,
+	}
+
+	testDryRuns(t, responses, []dryRunCase{
+		{
+			tool:    "resize_cache_instance",
+			handler: (*Handlers).ResizeCacheToolHandler,
+			args:    map[string]interface{}{"cache_id": "uredis-test", "size": float64(2)},
+			want:    []string{"ResizeURedisGroup"},
+		},
+	})
+}
@@ -0,0 +1,406 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ucloud/ucloud-mcp-server/pkg/ucloud"
+	"github.com/ucloud/ucloud-mcp-server/pkg/utils"
+	"github.com/ucloud/ucloud-sdk-go/services/uhost"
+	"github.com/ucloud/ucloud-sdk-go/services/vpc"
+)
+
+// vpcTopologyURI is the URI template of the VPC topology resource
+const vpcTopologyURI = "vpc://vpcs/{vpc_id}/topology"
+
+// registerVPCTools registers the VPC tools
+func (s *MCPServer) registerVPCTools() {
+	listVPCsTool := mcp.NewTool("list_vpcs",
+		mcp.WithDescription("List UCloud VPCs with their CIDRs"),
+		withProfile(),
+		withProjectID(),
+		withAllProjects(),
+	)
+	s.addTool(listVPCsTool, s.handlers.ListVPCsToolHandler, readOnlyTool)
+
+	listSubnetsTool := mcp.NewTool("list_subnets",
+		mcp.WithDescription("List UCloud subnets with their CIDRs and free addresses"),
+		mcp.WithString("vpc_id",
+			mcp.Description("Only list subnets of this VPC"),
+		),
+		withProfile(),
+		withProjectID(),
+		withAllProjects(),
+	)
+	s.addTool(listSubnetsTool, s.handlers.ListSubnetsToolHandler, readOnlyTool)
+
+	describeVPCTool := mcp.NewTool("describe_vpc",
+		mcp.WithDescription("Get a UCloud VPC with its CIDRs, subnets, route tables and attached resources"),
+		mcp.WithString("vpc_id",
+			mcp.Required(),
+			mcp.Description("ID of the VPC to describe"),
+		),
+		withProfile(),
+		withProjectID(),
+	)
+	s.addTool(describeVPCTool, s.handlers.DescribeVPCToolHandler, readOnlyTool)
+}
+
+// registerVPCResources registers the VPC resources
+func (s *MCPServer) registerVPCResources() {
+	s.addResourceTemplate(mcp.NewResourceTemplate(vpcTopologyURI, "vpc_topology",
+		mcp.WithTemplateDescription("Graph of the subnets of a UCloud VPC, the instances in each subnet and their IPs"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), s.handlers.VPCTopologyResourceHandler)
+}
+
+// ListVPCsToolHandler handles VPC list tool requests
+func (h *Handlers) ListVPCsToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	clients, err := h.clientsForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var allVPCs []interface{}
+	for _, client := range clients {
+		vpcs, err := client.ListVPCs()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list VPCs in project %s: %v", client.ProjectID(), err)), nil
+		}
+
+		for _, v := range vpcs {
+			if !isTagVisible(ctx, client, v.Tag) {
+				continue
+			}
+			vpcCopy := v // Create a copy to avoid using loop variable reference
+			info := ucloud.FormatVPCInfo(&vpcCopy)
+			info.ProjectID = client.ProjectID()
+			allVPCs = append(allVPCs, info)
+		}
+	}
+
+	log.Printf("Total VPCs found: %d", len(allVPCs))
+
+	jsonData, err := json.MarshalIndent(allVPCs, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal VPC data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// ListSubnetsToolHandler handles subnet list tool requests
+func (h *Handlers) ListSubnetsToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	vpcID := getStringArg(request, "vpc_id")
+
+	clients, err := h.clientsForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var allSubnets []interface{}
+	for _, client := range clients {
+		subnets, err := client.ListSubnets(vpcID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list subnets in project %s: %v", client.ProjectID(), err)), nil
+		}
+
+		for _, subnet := range subnets {
+			if !isTagVisible(ctx, client, subnet.Tag) {
+				continue
+			}
+			subnetCopy := subnet // Create a copy to avoid using loop variable reference
+			info := ucloud.FormatSubnetInfo(&subnetCopy)
+			info.ProjectID = client.ProjectID()
+			allSubnets = append(allSubnets, info)
+		}
+	}
+
+	log.Printf("Total subnets found: %d", len(allSubnets))
+
+	jsonData, err := json.MarshalIndent(allSubnets, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal subnet data: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// DescribeVPCToolHandler handles VPC description requests
+func (h *Handlers) DescribeVPCToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	vpcID := getStringArg(request, "vpc_id")
+
+	client, err := h.clientForRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	v, err := describeVisibleVPC(ctx, client, vpcID)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	topology, err := buildVPCTopology(ctx, client, v)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	tables, err := client.ListRouteTables(v.VPCId)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	routeTables := make([]*ucloud.RouteTableInfo, 0, len(tables))
+	for i := range tables {
+		routeTables = append(routeTables, ucloud.FormatRouteTableInfo(&tables[i]))
+	}
+
+	response := struct {
+		*vpcTopology
+		RouteTables []*ucloud.RouteTableInfo `json:"route_tables"`
+	}{topology, routeTables}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal VPC info: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// VPCTopologyResourceHandler handles VPC topology resource reads
+func (h *Handlers) VPCTopologyResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ctx = h.withGrant(ctx)
+	variables, err := utils.ParsePath(vpcTopologyURI, request.Params.URI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse path: %v", err)
+	}
+
+	vpcID := variables["vpc_id"]
+	if vpcID == "" {
+		return nil, fmt.Errorf("vpc_id not found in path")
+	}
+
+	client, err := h.resourceClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := describeVisibleVPC(ctx, client, vpcID)
+	if err != nil {
+		return nil, err
+	}
+
+	topology, err := buildVPCTopology(ctx, client, v)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(topology)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
+		},
+	}, nil
+}
+
+// vpcTopology is a VPC with its subnets, the resources in each subnet and their IPs
+type vpcTopology struct {
+	*ucloud.VPCInfo
+	Subnets []subnetTopology `json:"subnets"`
+}
+
+// subnetTopology is a subnet with the instances and other resources placed in it
+type subnetTopology struct {
+	*ucloud.SubnetInfo
+	Instances []instanceTopology `json:"instances"`
+	Resources []subnetResource   `json:"other_resources"`
+	// Hidden counts other resources left out because the caller may not see them
+	Hidden int `json:"hidden_resources,omitempty"`
+}
+
+// instanceTopology is an instance with all of its IPs
+type instanceTopology struct {
+	ID   string       `json:"id"`
+	Name string       `json:"name"`
+	IPs  []instanceIP `json:"ips"`
+}
+
+// instanceIP is a private or public IP of an instance
+type instanceIP struct {
+	IP       string `json:"ip"`
+	Type     string `json:"type"`
+	SubnetID string `json:"subnet_id,omitempty"`
+}
+
+// subnetResource is a non-instance resource in a subnet, e.g. a load balancer or database
+type subnetResource struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+	IP   string `json:"ip"`
+}
+
+// buildVPCTopology links the subnets of a VPC to the instances in them and their IPs.
+// Instances the caller may not see are left out.
+func buildVPCTopology(ctx context.Context, client *ucloud.UCloudClient, v *vpc.VPCInfo) (*vpcTopology, error) {
+	subnets, err := client.ListSubnets(v.VPCId)
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := client.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+	instancesByID := make(map[string]*uhost.UHostInstanceSet)
+	for i := range instances {
+		if isInstanceVisible(ctx, client, &instances[i]) {
+			instancesByID[instances[i].UHostId] = &instances[i]
+		}
+	}
+
+	others := &subnetResourceVisibility{ctx: ctx, client: client}
+
+	info := ucloud.FormatVPCInfo(v)
+	info.ProjectID = client.ProjectID()
+	topology := &vpcTopology{VPCInfo: info, Subnets: []subnetTopology{}}
+	for i := range subnets {
+		if !isTagVisible(ctx, client, subnets[i].Tag) {
+			continue
+		}
+
+		resources, err := client.SubnetResources(subnets[i].SubnetId)
+		if err != nil {
+			return nil, err
+		}
+
+		subnet := subnetTopology{
+			SubnetInfo: ucloud.FormatSubnetInfo(&subnets[i]),
+			Instances:  []instanceTopology{},
+			Resources:  []subnetResource{},
+		}
+		for _, resource := range resources {
+			if resource.ResourceType != ucloud.SubnetResourceUHost {
+				visible, err := others.isVisible(&resource)
+				if err != nil {
+					return nil, err
+				}
+				if !visible {
+					subnet.Hidden++
+					continue
+				}
+				subnet.Resources = append(subnet.Resources, subnetResource{
+					ID:   resource.ResourceId,
+					Type: resource.ResourceType,
+					Name: resource.Name,
+					IP:   resource.IP,
+				})
+				continue
+			}
+
+			instance, ok := instancesByID[resource.ResourceId]
+			if !ok {
+				continue
+			}
+			node := instanceTopology{ID: instance.UHostId, Name: instance.Name, IPs: []instanceIP{}}
+			for _, ip := range instance.IPSet {
+				node.IPs = append(node.IPs, instanceIP{IP: ip.IP, Type: ip.Type, SubnetID: ip.SubnetId})
+			}
+			subnet.Instances = append(subnet.Instances, node)
+		}
+		topology.Subnets = append(topology.Subnets, subnet)
+	}
+
+	return topology, nil
+}
+
+// Subnet resource types whose visibility can be checked
+const (
+	subnetResourceULB  = "ULB"
+	subnetResourceUMem = "UMEM"
+	subnetResourceUDB  = "UDB"
+)
+
+// subnetResourceVisibility decides which non-instance subnet resources a caller
+// may see. Subnet resources carry no tag, so for restricted callers load
+// balancers, caches and databases are looked up once each, and resources of
+// other types are hidden.
+type subnetResourceVisibility struct {
+	ctx     context.Context
+	client  *ucloud.UCloudClient
+	loaded  map[string]bool
+	visible map[string]bool
+}
+
+// isVisible reports whether the caller may see a subnet resource
+func (v *subnetResourceVisibility) isVisible(resource *vpc.SubnetResource) (bool, error) {
+	if !isRestricted(v.ctx, v.client) {
+		return true, nil
+	}
+	if err := v.load(resource.ResourceType); err != nil {
+		return false, err
+	}
+	return v.visible[resource.ResourceId], nil
+}
+
+// load records the visible resources of a type, once
+func (v *subnetResourceVisibility) load(resourceType string) error {
+	if v.loaded[resourceType] {
+		return nil
+	}
+	if v.loaded == nil {
+		v.loaded = make(map[string]bool)
+		v.visible = make(map[string]bool)
+	}
+
+	switch resourceType {
+	case subnetResourceULB:
+		lbs, err := v.client.ListLoadBalancers()
+		if err != nil {
+			return err
+		}
+		for _, lb := range lbs {
+			v.visible[lb.ULBId] = isTagVisible(v.ctx, v.client, lb.Tag)
+		}
+	case subnetResourceUMem:
+		caches, err := v.client.ListCaches()
+		if err != nil {
+			return err
+		}
+		for _, cache := range caches {
+			v.visible[cache.ID] = isTagVisible(v.ctx, v.client, cache.Tag)
+		}
+	case subnetResourceUDB:
+		dbs, err := v.client.ListDatabases()
+		if err != nil {
+			return err
+		}
+		for _, db := range dbs {
+			v.visible[db.DBId] = isTagVisible(v.ctx, v.client, db.Tag)
+		}
+	}
+	v.loaded[resourceType] = true
+	return nil
+}
+
+// describeVisibleVPC returns a VPC the caller may see
+func describeVisibleVPC(ctx context.Context, client *ucloud.UCloudClient, vpcID string) (*vpc.VPCInfo, error) {
+	v, err := client.DescribeVPC(vpcID)
+	if err != nil {
+		return nil, err
+	}
+	if !isTagVisible(ctx, client, v.Tag) {
+		return nil, fmt.Errorf("permission denied: VPC %s is outside the tags allowed for %s",
+			v.VPCId, IdentityFromContext(ctx))
+	}
+	return v, nil
+}
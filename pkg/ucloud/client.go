@@ -3,50 +3,324 @@ package ucloud
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+	"github.com/ucloud/ucloud-sdk-go/services/uaccount"
+	"github.com/ucloud/ucloud-sdk-go/services/udb"
+	"github.com/ucloud/ucloud-sdk-go/services/udisk"
+	"github.com/ucloud/ucloud-sdk-go/services/ufile"
 	"github.com/ucloud/ucloud-sdk-go/services/uhost"
+	"github.com/ucloud/ucloud-sdk-go/services/uk8s"
+	"github.com/ucloud/ucloud-sdk-go/services/ulb"
+	"github.com/ucloud/ucloud-sdk-go/services/umem"
+	"github.com/ucloud/ucloud-sdk-go/services/unet"
+	"github.com/ucloud/ucloud-sdk-go/services/vpc"
 	"github.com/ucloud/ucloud-sdk-go/ucloud"
 	"github.com/ucloud/ucloud-sdk-go/ucloud/auth"
 )
 
 // UCloudClient wraps the UCloud API client
 type UCloudClient struct {
-	UHostClient   *uhost.UHostClient
-	GenericClient *ucloud.Client
+	UHostClient    *uhost.UHostClient
+	UAccountClient *uaccount.UAccountClient
+	UDiskClient    *udisk.UDiskClient
+	UNetClient     *unet.UNetClient
+	VPCClient      *vpc.VPCClient
+	ULBClient      *ulb.ULBClient
+	UDBClient      *udb.UDBClient
+	UMemClient     *umem.UMemClient
+	UFileClient    *ufile.UFileClient
+	UK8SClient     *uk8s.UK8SClient
+	GenericClient  *ucloud.Client
+
+	config     ucloud.Config
+	credential auth.Credential
+	transport  http.RoundTripper
+	projects   []string
+	scope      *Scope
+	observer   func(APICall)
+	dryRun     bool
+	expires    time.Time
 }
 
-// NewUCloudClient creates a new UCloud client
-func NewUCloudClient(cfg *config.Config) (*UCloudClient, error) {
+// NewUCloudClient creates a new UCloud client for a credential profile.
+// httpCfg may be nil to use the default HTTP client settings.
+func NewUCloudClient(cfg *config.Profile, httpCfg *config.HTTPConfig) (*UCloudClient, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("configuration is nil")
 	}
+	if httpCfg == nil {
+		httpCfg = &config.HTTPConfig{}
+	}
 
 	// Create UCloud configuration
 	ucfg := ucloud.NewConfig()
 	ucfg.Region = cfg.Region
 	ucfg.ProjectId = cfg.ProjectID
-	ucfg.BaseUrl = "https://api.ucloud.cn"
+	ucfg.BaseUrl = DefaultBaseURL
+	if cfg.BaseURL != "" {
+		ucfg.BaseUrl = cfg.BaseURL
+	}
+	if err := applyHTTPConfig(&ucfg, httpCfg); err != nil {
+		return nil, err
+	}
+
+	transport, err := newTransport(httpCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve keys, which may come from files, environment variables or a credential helper
+	publicKey, publicExpires, err := config.ResolveSecret(cfg.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve public key: %v", err)
+	}
+	privateKey, privateExpires, err := config.ResolveSecret(cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve private key: %v", err)
+	}
+
+	var securityToken string
+	var tokenExpires time.Time
+	if cfg.SecurityToken != "" {
+		securityToken, tokenExpires, err = config.ResolveSecret(cfg.SecurityToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve security token: %v", err)
+		}
+	}
 
 	// Create credentials
 	credential := auth.NewCredential()
-	credential.PublicKey = cfg.PublicKey
-	credential.PrivateKey = cfg.PrivateKey
+	credential.PublicKey = publicKey
+	credential.PrivateKey = privateKey
+	credential.SecurityToken = securityToken
 
-	// Create UHost client
-	uhostClient := uhost.NewClient(&ucfg, &credential)
+	client := newClient(ucfg, credential, transport, cfg.Projects)
+	client.expires = earliest(earliest(publicExpires, privateExpires), tokenExpires)
+	return client, nil
+}
+
+// earliest returns the earlier of two expirations, where zero means never
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+// newClient creates the service clients sharing a single configuration
+func newClient(ucfg ucloud.Config, credential auth.Credential, transport http.RoundTripper, projects []string) *UCloudClient {
+	c := &UCloudClient{
+		config:     ucfg,
+		credential: credential,
+		transport:  transport,
+		projects:   projects,
+	}
+
+	// Create service clients
+	c.UHostClient = uhost.NewClient(&c.config, &c.credential)
+	c.UAccountClient = uaccount.NewClient(&c.config, &c.credential)
+	c.UDiskClient = udisk.NewClient(&c.config, &c.credential)
+	c.UNetClient = unet.NewClient(&c.config, &c.credential)
+	c.VPCClient = vpc.NewClient(&c.config, &c.credential)
+	c.ULBClient = ulb.NewClient(&c.config, &c.credential)
+	c.UDBClient = udb.NewClient(&c.config, &c.credential)
+	c.UMemClient = umem.NewClient(&c.config, &c.credential)
+	c.UFileClient = ufile.NewClient(&c.config, &c.credential)
+	c.UK8SClient = uk8s.NewClient(&c.config, &c.credential)
 
 	// Create generic client
-	genericClient := ucloud.NewClient(&ucfg, &credential)
+	c.GenericClient = ucloud.NewClient(&c.config, &c.credential)
+
+	// Route every service client through the configured proxy and CA bundle,
+	// and report API calls to the observer
+	for _, client := range c.serviceClients() {
+		client.SetTransport(transport)
+		client.AddResponseHandler(c.observe)
+	}
+
+	return c
+}
+
+// serviceClients returns the underlying SDK clients of every service
+func (c *UCloudClient) serviceClients() []*ucloud.Client {
+	return []*ucloud.Client{
+		c.UHostClient.Client,
+		c.UAccountClient.Client,
+		c.UDiskClient.Client,
+		c.UNetClient.Client,
+		c.VPCClient.Client,
+		c.ULBClient.Client,
+		c.UDBClient.Client,
+		c.UMemClient.Client,
+		c.UFileClient.Client,
+		c.UK8SClient.Client,
+		c.GenericClient,
+	}
+}
+
+// clone returns a copy of the client with a different configuration
+func (c *UCloudClient) clone(ucfg ucloud.Config) *UCloudClient {
+	client := newClient(ucfg, c.credential, c.transport, c.projects)
+	client.scope = c.scope
+	client.observer = c.observer
+	client.dryRun = c.dryRun
+	client.expires = c.expires
+	return client
+}
+
+// ProjectID returns the project the client is bound to
+func (c *UCloudClient) ProjectID() string {
+	return c.config.ProjectId
+}
+
+// Region returns the region the client is bound to
+func (c *UCloudClient) Region() string {
+	return c.config.Region
+}
+
+// BaseURL returns the API endpoint the client sends requests to
+func (c *UCloudClient) BaseURL() string {
+	return c.config.BaseUrl
+}
+
+// IsProjectAllowed reports whether the project is in the configured allow-list
+func (c *UCloudClient) IsProjectAllowed(projectID string) bool {
+	profile := config.Profile{Projects: c.projects}
+	return profile.IsProjectAllowed(projectID)
+}
+
+// WithProject returns a client bound to the given project.
+// An empty project ID returns the client itself.
+func (c *UCloudClient) WithProject(projectID string) (*UCloudClient, error) {
+	if projectID == "" || projectID == c.config.ProjectId {
+		return c, nil
+	}
+	if !c.IsProjectAllowed(projectID) {
+		return nil, fmt.Errorf("project %s is not allowed", projectID)
+	}
+
+	ucfg := c.config
+	ucfg.ProjectId = projectID
+	return c.clone(ucfg), nil
+}
+
+// IsScoped reports whether the client only sees resources in a restricted scope
+func (c *UCloudClient) IsScoped() bool {
+	return c.scope != nil
+}
+
+// ScopeContains reports whether a resource is within the client's scope
+func (c *UCloudClient) ScopeContains(id, tag string) bool {
+	return c.scope.ContainsResource(id, tag)
+}
+
+// IsExpired reports whether the client's resolved credentials have expired
+func (c *UCloudClient) IsExpired() bool {
+	return !c.expires.IsZero() && time.Now().After(c.expires)
+}
+
+// ListProjects gets the projects visible to the credentials, filtered by the allow-list
+func (c *UCloudClient) ListProjects() ([]uaccount.ProjectListInfo, error) {
+	req := c.UAccountClient.NewGetProjectListRequest()
+
+	resp, err := c.UAccountClient.GetProjectList(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %v", err)
+	}
+
+	var projects []uaccount.ProjectListInfo
+	for _, project := range resp.ProjectSet {
+		if c.IsProjectAllowed(project.ProjectId) {
+			projects = append(projects, project)
+		}
+	}
+
+	return projects, nil
+}
+
+// AllowedProjectIDs returns the project IDs that aggregated views should cover.
+// The allow-list is used when configured, otherwise all visible projects are listed.
+func (c *UCloudClient) AllowedProjectIDs() ([]string, error) {
+	if len(c.projects) > 0 {
+		return c.projects, nil
+	}
+
+	projects, err := c.ListProjects()
+	if err != nil {
+		return nil, err
+	}
 
-	return &UCloudClient{
-		UHostClient:   uhostClient,
-		GenericClient: genericClient,
-	}, nil
+	var ids []string
+	for _, project := range projects {
+		ids = append(ids, project.ProjectId)
+	}
+	return ids, nil
 }
 
-// DescribeInstance gets detailed information about an instance
+// Verify checks that the credentials, region and project are usable with
+// lightweight API calls. Failures are returned as a *Diagnosis.
+func (c *UCloudClient) Verify() error {
+	// Check the key pair and that the project exists. Sub-account keys may not
+	// be allowed to list projects, in which case the UHost call below decides.
+	projectsResp, err := c.UAccountClient.GetProjectList(c.UAccountClient.NewGetProjectListRequest())
+	switch {
+	case err != nil && !isPermissionError(err):
+		return diagnose(err)
+	case err == nil:
+		var available []string
+		found := false
+		for _, project := range projectsResp.ProjectSet {
+			available = append(available, project.ProjectId)
+			if project.ProjectId == c.ProjectID() {
+				found = true
+			}
+		}
+		if !found {
+			return &Diagnosis{
+				Problem: "wrong project",
+				Hint: fmt.Sprintf("project %s is not visible to this key, available projects: %s",
+					c.ProjectID(), strings.Join(available, ", ")),
+			}
+		}
+	}
+
+	// Check that the region is enabled for the account
+	regionsResp, err := c.UAccountClient.GetRegion(c.UAccountClient.NewGetRegionRequest())
+	if err == nil {
+		enabled := make(map[string]bool)
+		var available []string
+		for _, region := range regionsResp.Regions {
+			if !enabled[region.Region] {
+				enabled[region.Region] = true
+				available = append(available, region.Region)
+			}
+		}
+		if len(enabled) > 0 && !enabled[c.Region()] {
+			return &Diagnosis{
+				Problem: "region not enabled",
+				Hint: fmt.Sprintf("region %s is not enabled for this account, enabled regions: %s",
+					c.Region(), strings.Join(available, ", ")),
+			}
+		}
+	}
+
+	// Check UHost access in the region and project
+	limit := 1
+	req := c.UHostClient.NewDescribeUHostInstanceRequest()
+	req.Limit = &limit
+	if _, err := c.UHostClient.DescribeUHostInstance(req); err != nil {
+		return diagnose(err)
+	}
+
+	return nil
+}
+
+// DescribeInstance gets detailed information about an instance.
+// Instances outside the client's scope are reported as not found.
 func (c *UCloudClient) DescribeInstance(instanceID string) (*uhost.UHostInstanceSet, error) {
 	req := c.UHostClient.NewDescribeUHostInstanceRequest()
 	req.UHostIds = []string{instanceID}
@@ -56,14 +330,14 @@ func (c *UCloudClient) DescribeInstance(instanceID string) (*uhost.UHostInstance
 		return nil, fmt.Errorf("failed to describe instance %s: %v", instanceID, err)
 	}
 
-	if len(resp.UHostSet) == 0 {
+	if len(resp.UHostSet) == 0 || !c.scope.Contains(&resp.UHostSet[0]) {
 		return nil, fmt.Errorf("instance %s not found", instanceID)
 	}
 
 	return &resp.UHostSet[0], nil
 }
 
-// ListInstances gets a list of instances
+// ListInstances gets a list of the instances in the client's scope
 func (c *UCloudClient) ListInstances() ([]uhost.UHostInstanceSet, error) {
 	var allInstances []uhost.UHostInstanceSet
 	limit := 100
@@ -79,7 +353,11 @@ func (c *UCloudClient) ListInstances() ([]uhost.UHostInstanceSet, error) {
 			return nil, fmt.Errorf("failed to list instances: %v", err)
 		}
 
-		allInstances = append(allInstances, resp.UHostSet...)
+		for _, instance := range resp.UHostSet {
+			if c.scope.Contains(&instance) {
+				allInstances = append(allInstances, instance)
+			}
+		}
 
 		// If the number of instances is less than limit, we've got all data
 		if len(resp.UHostSet) < limit {
@@ -115,86 +393,64 @@ func (c *UCloudClient) GetInstanceMetrics(instance *uhost.UHostInstanceSet) ([]I
 	if instance == nil {
 		return nil, fmt.Errorf("instance is nil")
 	}
+	if !c.scope.Contains(instance) {
+		return nil, fmt.Errorf("instance %s not found", instance.UHostId)
+	}
 
-	var metrics []InstanceMetrics
-	limit := 100
-	offset := 0
-
-	for {
-		req := c.GenericClient.NewGenericRequest()
-		err := req.SetPayload(map[string]interface{}{
-			"Action":       "GetMetricOverview",
-			"Zone":         instance.Zone,
-			"ResourceType": "uhost",
-			"Limit":        limit,
-			"Offset":       offset,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to set payload: %v", err)
-		}
-
-		// Call monitoring API
-		metricResp, err := c.GenericClient.GenericInvoke(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get metrics: %v", err)
-		}
-
-		// Get DataSet from response
-		var metricsData struct {
-			RetCode      int               `json:"RetCode"`
-			Action       string            `json:"Action"`
-			ResourceType string            `json:"ResourceType"`
-			DataSet      []InstanceMetrics `json:"DataSet"`
-			RefreshTime  int64             `json:"RefreshTime"`
-			TotalCount   int               `json:"TotalCount"`
-		}
+	metrics, err := c.GetInstancesMetrics([]*uhost.UHostInstanceSet{instance})
+	if err != nil {
+		return nil, err
+	}
+	return metrics[instance.UHostId], nil
+}
 
-		payload := metricResp.GetPayload()
-		jsonBytes, err := json.Marshal(payload)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal payload: %v", err)
+// GetInstancesMetrics retrieves the monitoring metrics of several instances by ID,
+// paging through the metric overview once per zone. Instances out of scope are skipped.
+func (c *UCloudClient) GetInstancesMetrics(instances []*uhost.UHostInstanceSet) (map[string][]InstanceMetrics, error) {
+	wanted := make(map[string]map[string]bool)
+	for _, instance := range instances {
+		if instance == nil || !c.scope.Contains(instance) {
+			continue
 		}
-
-		if err := json.Unmarshal(jsonBytes, &metricsData); err != nil {
-			return nil, fmt.Errorf("failed to parse metrics data: %v", err)
+		if wanted[instance.Zone] == nil {
+			wanted[instance.Zone] = make(map[string]bool)
 		}
+		wanted[instance.Zone][instance.UHostId] = true
+	}
 
-		// Find monitoring data for the specified instance
-		for _, data := range metricsData.DataSet {
-			if data.ResourceId == instance.UHostId {
-				metrics = append(metrics, data)
+	metrics := make(map[string][]InstanceMetrics)
+	for zone, ids := range wanted {
+		err := c.metricOverview(zone, "uhost", func(data json.RawMessage) (bool, error) {
+			var entry InstanceMetrics
+			if err := json.Unmarshal(data, &entry); err != nil {
+				return false, fmt.Errorf("failed to parse metrics data: %v", err)
 			}
-		}
-
-		// If the number of data points is less than limit, we've got all data
-		if len(metricsData.DataSet) < limit {
-			break
-		}
-
-		// Update offset for next page
-		offset += limit
-
-		// If we've reached TotalCount, we can exit
-		if offset >= metricsData.TotalCount {
-			break
+			if ids[entry.ResourceId] {
+				metrics[entry.ResourceId] = append(metrics[entry.ResourceId], entry)
+			}
+			return true, nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
-
 	return metrics, nil
 }
 
 // InstanceInfo represents instance information for API response
 type InstanceInfo struct {
-	ID        string      `json:"id"`
-	Name      string      `json:"name"`
-	Status    string      `json:"status"`
-	IP        string      `json:"ip"`
-	Zone      string      `json:"zone"`
-	CPU       int         `json:"cpu"`
-	Memory    int         `json:"memory"`
-	DiskSize  int         `json:"disk_size"`
-	Metrics   interface{} `json:"metrics,omitempty"`
-	Timestamp string      `json:"timestamp,omitempty"`
+	ID        string        `json:"id"`
+	ProjectID string        `json:"project_id,omitempty"`
+	Name      string        `json:"name"`
+	Status    string        `json:"status"`
+	IP        string        `json:"ip"`
+	Zone      string        `json:"zone"`
+	CPU       int           `json:"cpu"`
+	Memory    int           `json:"memory"`
+	DiskSize  int           `json:"disk_size"`
+	Metrics   interface{}   `json:"metrics,omitempty"`
+	Firewall  *FirewallInfo `json:"firewall,omitempty"`
+	Timestamp string        `json:"timestamp,omitempty"`
 }
 
 // FormatInstanceInfo formats UHost instance information for API response
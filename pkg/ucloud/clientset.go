@@ -0,0 +1,148 @@
+package ucloud
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+)
+
+// ClientSet holds one UCloud client per named credential profile
+type ClientSet struct {
+	mu              sync.Mutex
+	clients         map[string]*UCloudClient
+	refreshing      map[string]*sync.Mutex
+	profiles        map[string]*config.Profile
+	http            config.HTTPConfig
+	scope           *Scope
+	names           []string
+	defaultProfile  string
+	requireExplicit bool
+}
+
+// NewClientSet creates clients for every profile in the configuration
+func NewClientSet(cfg *config.Config) (*ClientSet, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("configuration is nil")
+	}
+
+	set := &ClientSet{
+		clients:         make(map[string]*UCloudClient),
+		refreshing:      make(map[string]*sync.Mutex),
+		profiles:        make(map[string]*config.Profile),
+		names:           cfg.ProfileNames(),
+		defaultProfile:  cfg.DefaultProfile(),
+		requireExplicit: cfg.RequireExplicitProfile,
+		http:            cfg.HTTP,
+	}
+
+	scope, err := NewScope(cfg.Scope)
+	if err != nil {
+		return nil, err
+	}
+	set.scope = scope
+
+	for _, name := range set.names {
+		profile, err := cfg.GetProfile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		client, err := NewUCloudClient(profile, &set.http)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client for profile %s: %v", name, err)
+		}
+		client.scope = scope
+		set.clients[name] = client
+		set.profiles[name] = profile
+		set.refreshing[name] = &sync.Mutex{}
+	}
+
+	if _, ok := set.clients[set.defaultProfile]; !ok {
+		return nil, fmt.Errorf("active profile %q not found", set.defaultProfile)
+	}
+
+	return set, nil
+}
+
+// Client returns the client of the named profile, or the default profile if name is empty.
+// Clients whose credentials have expired are rebuilt from their profile.
+func (s *ClientSet) Client(name string) (*UCloudClient, error) {
+	if name == "" {
+		if s.requireExplicit && len(s.names) > 1 {
+			return nil, fmt.Errorf("profile argument is required, available profiles: %v", s.names)
+		}
+		name = s.defaultProfile
+	}
+
+	client, ok := s.current(name)
+	if !ok {
+		return nil, fmt.Errorf("profile %s not found", name)
+	}
+	if !client.IsExpired() {
+		return client, nil
+	}
+
+	// Credential helpers may take a while, so refresh without holding s.mu.
+	// Concurrent calls for the same profile wait for a single refresh.
+	refresh := s.refreshing[name]
+	refresh.Lock()
+	defer refresh.Unlock()
+
+	if client, _ = s.current(name); !client.IsExpired() {
+		return client, nil
+	}
+
+	log.Printf("Credentials of profile %s expired, refreshing", name)
+	refreshed, err := NewUCloudClient(s.profiles[name], &s.http)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh credentials of profile %s: %v", name, err)
+	}
+	refreshed.scope = s.scope
+
+	s.mu.Lock()
+	s.clients[name] = refreshed
+	s.mu.Unlock()
+	return refreshed, nil
+}
+
+// current returns the current client of the named profile
+func (s *ClientSet) current(name string) (*UCloudClient, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	client, ok := s.clients[name]
+	return client, ok
+}
+
+// Default returns the client of the default profile
+func (s *ClientSet) Default() (*UCloudClient, error) {
+	return s.Client(s.defaultProfile)
+}
+
+// DefaultProfile returns the name of the default profile
+func (s *ClientSet) DefaultProfile() string {
+	return s.defaultProfile
+}
+
+// Verify checks the credentials of every profile with lightweight API calls.
+// All failing profiles are reported, each with a diagnosis.
+func (s *ClientSet) Verify() error {
+	var errs []error
+	for _, name := range s.names {
+		client, err := s.Client(name)
+		if err == nil {
+			err = client.Verify()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("profile %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Profiles returns the names of all profiles in sorted order
+func (s *ClientSet) Profiles() []string {
+	return s.names
+}
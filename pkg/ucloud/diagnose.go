@@ -0,0 +1,71 @@
+package ucloud
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	uerr "github.com/ucloud/ucloud-sdk-go/ucloud/error"
+)
+
+// retCodeSignatureError is returned by UCloud when the request signature does not match
+const retCodeSignatureError = 171
+
+// Diagnosis explains why a profile cannot be used and how to fix it
+type Diagnosis struct {
+	// Problem is a short description such as "invalid key pair"
+	Problem string
+
+	// Hint tells the operator what to check
+	Hint string
+
+	// Err is the underlying error, if any
+	Err error
+}
+
+// Error formats the diagnosis as a single line
+func (d *Diagnosis) Error() string {
+	if d.Err != nil {
+		return fmt.Sprintf("%s: %s (%v)", d.Problem, d.Hint, d.Err)
+	}
+	return fmt.Sprintf("%s: %s", d.Problem, d.Hint)
+}
+
+// Unwrap returns the underlying error
+func (d *Diagnosis) Unwrap() error {
+	return d.Err
+}
+
+// diagnose classifies an error returned by a UCloud API call
+func diagnose(err error) *Diagnosis {
+	var sdkErr uerr.Error
+	if !errors.As(err, &sdkErr) {
+		return &Diagnosis{Problem: "request failed", Hint: "check the base URL and network connectivity", Err: err}
+	}
+
+	message := strings.ToLower(sdkErr.Message())
+	switch {
+	case sdkErr.Name() == uerr.ErrNetwork || sdkErr.Name() == uerr.ErrSendRequest:
+		return &Diagnosis{Problem: "API endpoint unreachable", Hint: "check base_url, proxy settings and network connectivity", Err: err}
+	case sdkErr.Name() == uerr.ErrCredentialExpired:
+		return &Diagnosis{Problem: "credentials expired", Hint: "check the credential helper output", Err: err}
+	case sdkErr.Code() == retCodeSignatureError || strings.Contains(message, "signature"):
+		return &Diagnosis{Problem: "invalid key pair", Hint: "UCloud rejected the request signature, check public_key and private_key", Err: err}
+	case strings.Contains(message, "publickey") || strings.Contains(message, "public key"):
+		return &Diagnosis{Problem: "invalid public key", Hint: "the public key does not exist or is disabled, check public_key", Err: err}
+	case strings.Contains(message, "region"):
+		return &Diagnosis{Problem: "region not available", Hint: "check that region is spelled correctly and enabled for the account", Err: err}
+	case strings.Contains(message, "project"):
+		return &Diagnosis{Problem: "wrong project", Hint: "check project_id and that the key belongs to a member of the project", Err: err}
+	case strings.Contains(message, "permission") || strings.Contains(message, "denied") || strings.Contains(message, "no auth"):
+		return &Diagnosis{Problem: "permission denied", Hint: "grant the key's user read access to UHost in this project", Err: err}
+	}
+
+	return &Diagnosis{Problem: "API call failed", Hint: "see the UCloud error for details", Err: err}
+}
+
+// isPermissionError reports whether an error means the key lacks access to an action
+func isPermissionError(err error) bool {
+	d := diagnose(err)
+	return d.Problem == "permission denied"
+}
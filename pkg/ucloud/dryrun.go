@@ -0,0 +1,113 @@
+package ucloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// dryRunHiddenParams are request parameters left out of planned requests
+var dryRunHiddenParams = []string{"PublicKey", "Signature"}
+
+// dryRunID stands in for the ID of a resource that a dry run would have created
+const dryRunID = "dry-run"
+
+// PlannedRequest is a UCloud API request that a dry run would have sent
+type PlannedRequest struct {
+	Action string                 `json:"action"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// DryRun records API requests instead of sending them. Every request succeeds
+// with an empty response.
+type DryRun struct {
+	mu       sync.Mutex
+	requests []PlannedRequest
+}
+
+// Requests returns the recorded requests in the order they were made
+func (d *DryRun) Requests() []PlannedRequest {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]PlannedRequest(nil), d.requests...)
+}
+
+// RoundTrip records the request and returns a successful empty response
+func (d *DryRun) RoundTrip(req *http.Request) (*http.Response, error) {
+	params, err := requestParams(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode request: %v", err)
+	}
+	for _, name := range dryRunHiddenParams {
+		delete(params, name)
+	}
+
+	action, _ := params["Action"].(string)
+	delete(params, "Action")
+
+	d.mu.Lock()
+	d.requests = append(d.requests, PlannedRequest{Action: action, Params: params})
+	d.mu.Unlock()
+
+	body := fmt.Sprintf(`{"RetCode":0,"Action":%q}`, action+"Response")
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// requestParams decodes the parameters of a form or JSON encoded API request
+func requestParams(req *http.Request) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+	for name, values := range req.URL.Query() {
+		params[name] = values[0]
+	}
+	if req.Body == nil {
+		return params, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return params, nil
+	}
+
+	if strings.Contains(req.Header.Get("Content-Type"), "json") {
+		if err := json.Unmarshal(body, &params); err != nil {
+			return nil, err
+		}
+		return params, nil
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range form {
+		params[name] = values[0]
+	}
+	return params, nil
+}
+
+// WithDryRun returns a copy of the client that records its requests in d instead of sending them
+func (c *UCloudClient) WithDryRun(d *DryRun) *UCloudClient {
+	client := newClient(c.config, c.credential, d, c.projects)
+	client.scope = c.scope
+	client.expires = c.expires
+	client.dryRun = true
+	return client
+}
+
+// IsDryRun reports whether the client only records requests
+func (c *UCloudClient) IsDryRun() bool {
+	return c.dryRun
+}
@@ -0,0 +1,282 @@
+package ucloud
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ucloud/ucloud-sdk-go/services/uhost"
+	"github.com/ucloud/ucloud-sdk-go/services/unet"
+	"github.com/ucloud/ucloud-sdk-go/ucloud"
+)
+
+// EIP states reported by UNet
+const (
+	EIPStatusUsed = "used"
+	EIPStatusFree = "free"
+)
+
+// ResourceTypeUHost is the UNet resource type of UHost instances, as EIPs and firewalls report it
+const ResourceTypeUHost = "uhost"
+
+// ListEIPs gets the EIPs in the client's scope
+func (c *UCloudClient) ListEIPs() ([]unet.UnetEIPSet, error) {
+	var allEIPs []unet.UnetEIPSet
+	check := c.newScopeCheck()
+	limit := 100
+	offset := 0
+
+	for {
+		req := c.UNetClient.NewDescribeEIPRequest()
+		req.Limit = &limit
+		req.Offset = &offset
+
+		resp, err := c.UNetClient.DescribeEIP(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list EIPs: %v", err)
+		}
+
+		for _, eip := range resp.EIPSet {
+			ok, err := check.contains(eip.EIPId, eip.Tag, eipHost(&eip))
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				allEIPs = append(allEIPs, eip)
+			}
+		}
+
+		// If the number of EIPs is less than limit, we've got all data
+		if len(resp.EIPSet) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+	}
+
+	return allEIPs, nil
+}
+
+// DescribeEIP gets detailed information about an EIP.
+// EIPs outside the client's scope are reported as not found.
+func (c *UCloudClient) DescribeEIP(eipID string) (*unet.UnetEIPSet, error) {
+	req := c.UNetClient.NewDescribeEIPRequest()
+	req.EIPIds = []string{eipID}
+
+	resp, err := c.UNetClient.DescribeEIP(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe EIP %s: %v", eipID, err)
+	}
+
+	if len(resp.EIPSet) == 0 {
+		return nil, fmt.Errorf("EIP %s not found", eipID)
+	}
+	eip := &resp.EIPSet[0]
+	ok, err := c.newScopeCheck().contains(eip.EIPId, eip.Tag, eipHost(eip))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("EIP %s not found", eipID)
+	}
+
+	return eip, nil
+}
+
+// eipHost returns the ID of the instance an EIP is bound to, if any
+func eipHost(eip *unet.UnetEIPSet) string {
+	if eip.Resource.ResourceType != ResourceTypeUHost {
+		return ""
+	}
+	return eip.Resource.ResourceID
+}
+
+// FindEIPByIP gets the EIP with the given public IP address, or nil if there is none
+// in scope. found reports whether an EIP has the address at all, even out of scope.
+func (c *UCloudClient) FindEIPByIP(ip string) (eip *unet.UnetEIPSet, found bool, err error) {
+	req := c.UNetClient.NewDescribeEIPRequest()
+	req.IPs = []string{ip}
+
+	resp, err := c.UNetClient.DescribeEIP(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to look up IP %s: %v", ip, err)
+	}
+
+	check := c.newScopeCheck()
+	for i := range resp.EIPSet {
+		for _, addr := range resp.EIPSet[i].EIPAddr {
+			if addr.IP != ip {
+				continue
+			}
+			found = true
+			ok, err := check.contains(resp.EIPSet[i].EIPId, resp.EIPSet[i].Tag, eipHost(&resp.EIPSet[i]))
+			if err != nil {
+				return nil, found, err
+			}
+			if ok {
+				return &resp.EIPSet[i], found, nil
+			}
+		}
+	}
+	return nil, found, nil
+}
+
+// FindInstanceByIP gets the instance in the client's scope that has the given
+// public or private IP address, or nil if there is none
+func (c *UCloudClient) FindInstanceByIP(ip string) (*uhost.UHostInstanceSet, error) {
+	instances, err := c.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range instances {
+		for _, ipSet := range instances[i].IPSet {
+			if ipSet.IP == ip {
+				return &instances[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// EIPSpec describes an EIP to allocate
+type EIPSpec struct {
+	Name         string
+	Bandwidth    int
+	OperatorName string
+	PayMode      string
+	ChargeType   string
+	Tag          string
+}
+
+// AllocateEIP allocates an EIP and returns its ID and address
+func (c *UCloudClient) AllocateEIP(spec EIPSpec) (string, string, error) {
+	req := c.UNetClient.NewAllocateEIPRequest()
+	req.Bandwidth = &spec.Bandwidth
+	req.OperatorName = &spec.OperatorName
+	if spec.Name != "" {
+		req.Name = &spec.Name
+	}
+	if spec.PayMode != "" {
+		req.PayMode = &spec.PayMode
+	}
+	if spec.ChargeType != "" {
+		req.ChargeType = &spec.ChargeType
+	}
+	if spec.Tag != "" {
+		req.Tag = &spec.Tag
+	}
+
+	resp, err := c.UNetClient.AllocateEIP(req)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to allocate EIP: %v", err)
+	}
+
+	if len(resp.EIPSet) == 0 {
+		if c.dryRun {
+			return dryRunID, "", nil
+		}
+		return "", "", fmt.Errorf("failed to allocate EIP: response has no EIP ID")
+	}
+	eip := resp.EIPSet[0]
+	ip := ""
+	if len(eip.EIPAddr) > 0 {
+		ip = eip.EIPAddr[0].IP
+	}
+	return eip.EIPId, ip, nil
+}
+
+// BindEIP binds an EIP to an instance
+func (c *UCloudClient) BindEIP(eipID, hostID string) error {
+	req := c.UNetClient.NewBindEIPRequest()
+	req.EIPId = &eipID
+	req.ResourceId = &hostID
+	req.ResourceType = ucloud.String(ResourceTypeUHost)
+
+	if _, err := c.UNetClient.BindEIP(req); err != nil {
+		return fmt.Errorf("failed to bind EIP %s to %s: %v", eipID, hostID, err)
+	}
+	return nil
+}
+
+// UnbindEIP unbinds an EIP from the resource it is bound to
+func (c *UCloudClient) UnbindEIP(eip *unet.UnetEIPSet) error {
+	req := c.UNetClient.NewUnBindEIPRequest()
+	req.EIPId = &eip.EIPId
+	req.ResourceId = &eip.Resource.ResourceID
+	req.ResourceType = &eip.Resource.ResourceType
+
+	if _, err := c.UNetClient.UnBindEIP(req); err != nil {
+		return fmt.Errorf("failed to unbind EIP %s from %s: %v", eip.EIPId, eip.Resource.ResourceID, err)
+	}
+	return nil
+}
+
+// ReleaseEIP releases an unbound EIP
+func (c *UCloudClient) ReleaseEIP(eipID string) error {
+	req := c.UNetClient.NewReleaseEIPRequest()
+	req.EIPId = &eipID
+
+	if _, err := c.UNetClient.ReleaseEIP(req); err != nil {
+		return fmt.Errorf("failed to release EIP %s: %v", eipID, err)
+	}
+	return nil
+}
+
+// ModifyEIPBandwidth changes the bandwidth of an EIP in Mbps
+func (c *UCloudClient) ModifyEIPBandwidth(eipID string, bandwidth int) error {
+	req := c.UNetClient.NewModifyEIPBandwidthRequest()
+	req.EIPId = &eipID
+	req.Bandwidth = &bandwidth
+
+	if _, err := c.UNetClient.ModifyEIPBandwidth(req); err != nil {
+		return fmt.Errorf("failed to modify bandwidth of EIP %s: %v", eipID, err)
+	}
+	return nil
+}
+
+// EIPInfo represents EIP information for API response
+type EIPInfo struct {
+	ID           string   `json:"id"`
+	ProjectID    string   `json:"project_id,omitempty"`
+	Name         string   `json:"name"`
+	IPs          []string `json:"ips"`
+	Status       string   `json:"status"`
+	Bandwidth    int      `json:"bandwidth"`
+	PayMode      string   `json:"pay_mode"`
+	ChargeType   string   `json:"charge_type,omitempty"`
+	Tag          string   `json:"tag,omitempty"`
+	ResourceID   string   `json:"resource_id,omitempty"`
+	ResourceType string   `json:"resource_type,omitempty"`
+	ResourceName string   `json:"resource_name,omitempty"`
+	Remark       string   `json:"remark,omitempty"`
+	CreateTime   string   `json:"create_time,omitempty"`
+}
+
+// FormatEIPInfo formats EIP information for API response
+func FormatEIPInfo(eip *unet.UnetEIPSet) *EIPInfo {
+	if eip == nil {
+		return nil
+	}
+
+	ips := make([]string, 0, len(eip.EIPAddr))
+	for _, addr := range eip.EIPAddr {
+		ips = append(ips, addr.IP)
+	}
+
+	return &EIPInfo{
+		ID:           eip.EIPId,
+		Name:         eip.Name,
+		IPs:          ips,
+		Status:       eip.Status,
+		Bandwidth:    eip.Bandwidth,
+		PayMode:      eip.PayMode,
+		ChargeType:   eip.ChargeType,
+		Tag:          eip.Tag,
+		ResourceID:   eip.Resource.ResourceID,
+		ResourceType: eip.Resource.ResourceType,
+		ResourceName: eip.Resource.ResourceName,
+		Remark:       eip.Remark,
+		CreateTime:   time.Unix(int64(eip.CreateTime), 0).Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,294 @@
+package ucloud
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ucloud/ucloud-sdk-go/services/unet"
+	"github.com/ucloud/ucloud-sdk-go/ucloud"
+)
+
+// FirewallRule is a single firewall rule
+type FirewallRule struct {
+	Protocol string `json:"protocol"`
+	Port     string `json:"port,omitempty"`
+	Source   string `json:"source"`
+	Action   string `json:"action"`
+	Priority string `json:"priority"`
+	Remark   string `json:"remark,omitempty"`
+}
+
+// String formats the rule the way UpdateFirewall expects it, e.g. "TCP|22|0.0.0.0/0|ACCEPT|HIGH|ssh"
+func (r FirewallRule) String() string {
+	return strings.Join([]string{r.Protocol, r.Port, r.Source, r.Action, r.Priority, r.Remark}, "|")
+}
+
+// Validate checks that the rule is complete and uses known values
+func (r FirewallRule) Validate() error {
+	switch r.Protocol {
+	case "TCP", "UDP", "ICMP", "GRE", "ICMPv6":
+	default:
+		return fmt.Errorf("protocol must be TCP, UDP, ICMP, GRE or ICMPv6, got %q", r.Protocol)
+	}
+	if (r.Protocol == "TCP" || r.Protocol == "UDP") && r.Port == "" {
+		return fmt.Errorf("port is required for %s rules", r.Protocol)
+	}
+	if r.Source == "" {
+		return fmt.Errorf("source is required")
+	}
+	switch r.Action {
+	case "ACCEPT", "DROP":
+	default:
+		return fmt.Errorf("action must be ACCEPT or DROP, got %q", r.Action)
+	}
+	switch r.Priority {
+	case "HIGH", "MEDIUM", "LOW":
+	default:
+		return fmt.Errorf("priority must be HIGH, MEDIUM or LOW, got %q", r.Priority)
+	}
+	if strings.Contains(r.Remark, "|") {
+		return fmt.Errorf("remark must not contain \"|\"")
+	}
+	return nil
+}
+
+// FirewallDiff lists the rules a rule update adds and removes
+type FirewallDiff struct {
+	Added     []FirewallRule `json:"added"`
+	Removed   []FirewallRule `json:"removed"`
+	Unchanged int            `json:"unchanged"`
+}
+
+// DiffFirewallRules compares the current rules of a firewall with new ones.
+// A rule whose remark changes is reported as removed and added again.
+func DiffFirewallRules(current, updated []FirewallRule) *FirewallDiff {
+	diff := &FirewallDiff{Added: []FirewallRule{}, Removed: []FirewallRule{}}
+
+	remaining := make(map[string]int)
+	for _, rule := range current {
+		remaining[rule.String()]++
+	}
+	for _, rule := range updated {
+		if remaining[rule.String()] > 0 {
+			remaining[rule.String()]--
+			diff.Unchanged++
+		} else {
+			diff.Added = append(diff.Added, rule)
+		}
+	}
+	for _, rule := range current {
+		if remaining[rule.String()] > 0 {
+			remaining[rule.String()]--
+			diff.Removed = append(diff.Removed, rule)
+		}
+	}
+	return diff
+}
+
+// ListFirewalls gets the firewalls in the client's scope
+func (c *UCloudClient) ListFirewalls() ([]unet.FirewallDataSet, error) {
+	var allFirewalls []unet.FirewallDataSet
+	check := c.newScopeCheck()
+	limit := 100
+	offset := 0
+
+	for {
+		req := c.UNetClient.NewDescribeFirewallRequest()
+		req.Limit = &limit
+		req.Offset = &offset
+
+		resp, err := c.UNetClient.DescribeFirewall(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list firewalls: %v", err)
+		}
+
+		for _, firewall := range resp.DataSet {
+			ok, err := c.firewallInScope(check, &firewall)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				allFirewalls = append(allFirewalls, firewall)
+			}
+		}
+
+		// If the number of firewalls is less than limit, we've got all data
+		if len(resp.DataSet) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+	}
+
+	return allFirewalls, nil
+}
+
+// DescribeFirewall gets detailed information about a firewall.
+// Firewalls outside the client's scope are reported as not found.
+func (c *UCloudClient) DescribeFirewall(firewallID string) (*unet.FirewallDataSet, error) {
+	req := c.UNetClient.NewDescribeFirewallRequest()
+	req.FWId = &firewallID
+
+	resp, err := c.UNetClient.DescribeFirewall(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe firewall %s: %v", firewallID, err)
+	}
+
+	if len(resp.DataSet) == 0 {
+		return nil, fmt.Errorf("firewall %s not found", firewallID)
+	}
+	firewall := &resp.DataSet[0]
+	ok, err := c.firewallInScope(c.newScopeCheck(), firewall)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("firewall %s not found", firewallID)
+	}
+
+	return firewall, nil
+}
+
+// firewallInScope reports whether a firewall, or an instance it is applied to, is in scope
+func (c *UCloudClient) firewallInScope(check *scopeCheck, firewall *unet.FirewallDataSet) (bool, error) {
+	if c.scope.ContainsResource(firewall.FWId, firewall.Tag) {
+		return true, nil
+	}
+	if firewall.ResourceCount == 0 {
+		return false, nil
+	}
+	resources, err := c.FirewallResources(firewall.FWId)
+	if err != nil {
+		return false, err
+	}
+	for _, resource := range resources {
+		if resource.ResourceType != ResourceTypeUHost {
+			continue
+		}
+		ok, err := check.contains("", "", resource.ResourceID)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// InstanceFirewall gets the firewall applied to an instance, or nil if there is none
+func (c *UCloudClient) InstanceFirewall(hostID string) (*unet.FirewallDataSet, error) {
+	req := c.UNetClient.NewDescribeFirewallRequest()
+	req.ResourceId = &hostID
+	req.ResourceType = ucloud.String(ResourceTypeUHost)
+
+	resp, err := c.UNetClient.DescribeFirewall(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get firewall of instance %s: %v", hostID, err)
+	}
+
+	if len(resp.DataSet) == 0 {
+		return nil, nil
+	}
+	return &resp.DataSet[0], nil
+}
+
+// FirewallResources gets the resources a firewall is applied to
+func (c *UCloudClient) FirewallResources(firewallID string) ([]unet.ResourceSet, error) {
+	var allResources []unet.ResourceSet
+	limit := 100
+	offset := 0
+
+	for {
+		req := c.UNetClient.NewDescribeFirewallResourceRequest()
+		req.FWId = &firewallID
+		req.Limit = &limit
+		req.Offset = &offset
+
+		resp, err := c.UNetClient.DescribeFirewallResource(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resources of firewall %s: %v", firewallID, err)
+		}
+		allResources = append(allResources, resp.ResourceSet...)
+
+		// If the number of resources is less than limit, we've got all data
+		if len(resp.ResourceSet) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+	}
+
+	return allResources, nil
+}
+
+// UpdateFirewallRules replaces all rules of a firewall
+func (c *UCloudClient) UpdateFirewallRules(firewallID string, rules []FirewallRule) error {
+	req := c.UNetClient.NewUpdateFirewallRequest()
+	req.FWId = &firewallID
+	for _, rule := range rules {
+		req.Rule = append(req.Rule, rule.String())
+	}
+
+	if _, err := c.UNetClient.UpdateFirewall(req); err != nil {
+		return fmt.Errorf("failed to update rules of firewall %s: %v", firewallID, err)
+	}
+	return nil
+}
+
+// GrantFirewall applies a firewall to an instance, replacing its current firewall
+func (c *UCloudClient) GrantFirewall(firewallID, hostID string) error {
+	req := c.UNetClient.NewGrantFirewallRequest()
+	req.FWId = &firewallID
+	req.ResourceId = &hostID
+	req.ResourceType = ucloud.String(ResourceTypeUHost)
+
+	if _, err := c.UNetClient.GrantFirewall(req); err != nil {
+		return fmt.Errorf("failed to apply firewall %s to %s: %v", firewallID, hostID, err)
+	}
+	return nil
+}
+
+// FirewallInfo represents firewall information for API response
+type FirewallInfo struct {
+	ID            string         `json:"id"`
+	ProjectID     string         `json:"project_id,omitempty"`
+	Name          string         `json:"name"`
+	Type          string         `json:"type"`
+	Tag           string         `json:"tag,omitempty"`
+	Remark        string         `json:"remark,omitempty"`
+	ResourceCount int            `json:"resource_count"`
+	Rules         []FirewallRule `json:"rules"`
+}
+
+// FirewallRules converts the rules of a firewall to structured rules
+func FirewallRules(firewall *unet.FirewallDataSet) []FirewallRule {
+	rules := make([]FirewallRule, 0, len(firewall.Rule))
+	for _, rule := range firewall.Rule {
+		rules = append(rules, FirewallRule{
+			Protocol: rule.ProtocolType,
+			Port:     rule.DstPort,
+			Source:   rule.SrcIP,
+			Action:   rule.RuleAction,
+			Priority: rule.Priority,
+			Remark:   rule.Remark,
+		})
+	}
+	return rules
+}
+
+// FormatFirewallInfo formats firewall information for API response
+func FormatFirewallInfo(firewall *unet.FirewallDataSet) *FirewallInfo {
+	if firewall == nil {
+		return nil
+	}
+
+	return &FirewallInfo{
+		ID:            firewall.FWId,
+		Name:          firewall.Name,
+		Type:          firewall.Type,
+		Tag:           firewall.Tag,
+		Remark:        firewall.Remark,
+		ResourceCount: firewall.ResourceCount,
+		Rules:         FirewallRules(firewall),
+	}
+}
@@ -0,0 +1,51 @@
+package ucloud
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffFirewallRules(t *testing.T) {
+	ssh := FirewallRule{Protocol: "TCP", Port: "22", Source: "0.0.0.0/0", Action: "ACCEPT", Priority: "HIGH", Remark: "ssh"}
+	web := FirewallRule{Protocol: "TCP", Port: "80", Source: "0.0.0.0/0", Action: "ACCEPT", Priority: "HIGH"}
+	ping := FirewallRule{Protocol: "ICMP", Source: "0.0.0.0/0", Action: "ACCEPT", Priority: "LOW"}
+	sshRenamed := ssh
+	sshRenamed.Remark = "admin ssh"
+
+	tests := []struct {
+		name      string
+		current   []FirewallRule
+		updated   []FirewallRule
+		added     []FirewallRule
+		removed   []FirewallRule
+		unchanged int
+	}{
+		{"same rules", []FirewallRule{ssh, web}, []FirewallRule{web, ssh}, nil, nil, 2},
+		{"rule added", []FirewallRule{ssh}, []FirewallRule{ssh, web}, []FirewallRule{web}, nil, 1},
+		{"rule removed", []FirewallRule{ssh, ping}, []FirewallRule{ssh}, nil, []FirewallRule{ping}, 1},
+		{"remark changed", []FirewallRule{ssh}, []FirewallRule{sshRenamed}, []FirewallRule{sshRenamed}, []FirewallRule{ssh}, 0},
+		{"duplicate removed", []FirewallRule{web, web}, []FirewallRule{web}, nil, []FirewallRule{web}, 1},
+		{"all replaced", []FirewallRule{ssh}, []FirewallRule{ping}, []FirewallRule{ping}, []FirewallRule{ssh}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffFirewallRules(tt.current, tt.updated)
+			if tt.added == nil {
+				tt.added = []FirewallRule{}
+			}
+			if tt.removed == nil {
+				tt.removed = []FirewallRule{}
+			}
+			if !reflect.DeepEqual(diff.Added, tt.added) {
+				t.Errorf("Added = %v, want %v", diff.Added, tt.added)
+			}
+			if !reflect.DeepEqual(diff.Removed, tt.removed) {
+				t.Errorf("Removed = %v, want %v", diff.Removed, tt.removed)
+			}
+			if diff.Unchanged != tt.unchanged {
+				t.Errorf("Unchanged = %d, want %d", diff.Unchanged, tt.unchanged)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package ucloud
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+	"github.com/ucloud/ucloud-mcp-server/pkg/version"
+	"github.com/ucloud/ucloud-sdk-go/ucloud"
+)
+
+// DefaultBaseURL is the UCloud API endpoint used when a profile does not set one
+const DefaultBaseURL = "https://api.ucloud.cn"
+
+// newTransport creates the HTTP transport for API requests from the HTTP settings
+func newTransport(cfg *config.HTTPConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %v", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %v", err)
+		}
+
+		// Extend the system roots rather than replacing them
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.CABundle)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return transport, nil
+}
+
+// userAgent returns the user agent appended to the SDK's own, identifying the server version
+func userAgent(cfg *config.HTTPConfig) string {
+	if cfg.UserAgent != "" {
+		return version.UserAgent() + " " + cfg.UserAgent
+	}
+	return version.UserAgent()
+}
+
+// applyHTTPConfig applies the timeout and retry settings to an SDK configuration
+func applyHTTPConfig(ucfg *ucloud.Config, cfg *config.HTTPConfig) error {
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid HTTP timeout: %v", err)
+		}
+		ucfg.Timeout = timeout
+	}
+	if cfg.MaxRetries > 0 {
+		ucfg.MaxRetries = cfg.MaxRetries
+	}
+	ucfg.UserAgent = userAgent(cfg)
+	return nil
+}
@@ -0,0 +1,88 @@
+package ucloud
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ResourceMetrics is the latest monitoring data of a resource, keyed by metric name
+type ResourceMetrics map[string]interface{}
+
+// GetResourceMetrics retrieves the latest monitoring metrics of a resource of the given
+// type (e.g. "udb" or "umem"). It returns nil if the monitoring service has no data for it.
+func (c *UCloudClient) GetResourceMetrics(zone, resourceType, resourceID string) (ResourceMetrics, error) {
+	var found ResourceMetrics
+	err := c.metricOverview(zone, resourceType, func(data json.RawMessage) (bool, error) {
+		var metrics ResourceMetrics
+		if err := json.Unmarshal(data, &metrics); err != nil {
+			return false, fmt.Errorf("failed to parse metrics data: %v", err)
+		}
+		if metrics["ResourceId"] == resourceID {
+			found = metrics
+			return false, nil
+		}
+		return true, nil
+	})
+	return found, err
+}
+
+// metricOverview pages through the latest monitoring data of every resource of a type
+// in a zone, passing each entry to visit until visit returns false or an error
+func (c *UCloudClient) metricOverview(zone, resourceType string, visit func(data json.RawMessage) (bool, error)) error {
+	limit := 100
+	offset := 0
+
+	for {
+		req := c.GenericClient.NewGenericRequest()
+		err := req.SetPayload(map[string]interface{}{
+			"Action":       "GetMetricOverview",
+			"Zone":         zone,
+			"ResourceType": resourceType,
+			"Limit":        limit,
+			"Offset":       offset,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set payload: %v", err)
+		}
+
+		metricResp, err := c.GenericClient.GenericInvoke(req)
+		if err != nil {
+			return fmt.Errorf("failed to get metrics: %v", err)
+		}
+
+		var metricsData struct {
+			DataSet    []json.RawMessage `json:"DataSet"`
+			TotalCount int               `json:"TotalCount"`
+		}
+
+		jsonBytes, err := json.Marshal(metricResp.GetPayload())
+		if err != nil {
+			return fmt.Errorf("failed to marshal payload: %v", err)
+		}
+		if err := json.Unmarshal(jsonBytes, &metricsData); err != nil {
+			return fmt.Errorf("failed to parse metrics data: %v", err)
+		}
+
+		for _, data := range metricsData.DataSet {
+			more, err := visit(data)
+			if err != nil || !more {
+				return err
+			}
+		}
+
+		// If the number of data points is less than limit, we've got all data
+		if len(metricsData.DataSet) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+
+		// If we've reached TotalCount, we can exit
+		if offset >= metricsData.TotalCount {
+			break
+		}
+	}
+
+	return nil
+}
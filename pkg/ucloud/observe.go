@@ -0,0 +1,45 @@
+package ucloud
+
+import (
+	"github.com/ucloud/ucloud-sdk-go/ucloud"
+	"github.com/ucloud/ucloud-sdk-go/ucloud/request"
+	"github.com/ucloud/ucloud-sdk-go/ucloud/response"
+)
+
+// APICall describes a UCloud API request sent by a client
+type APICall struct {
+	Action    string `json:"action"`
+	Region    string `json:"region,omitempty"`
+	ProjectID string `json:"project_id,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// WithObserver returns a copy of the client that reports every API call to observe
+func (c *UCloudClient) WithObserver(observe func(APICall)) *UCloudClient {
+	client := c.clone(c.config)
+	client.observer = observe
+	return client
+}
+
+// observe is an SDK response handler that reports the call to the client's observer
+func (c *UCloudClient) observe(_ *ucloud.Client, req request.Common, resp response.Common, err error) (response.Common, error) {
+	if c.observer == nil {
+		return resp, err
+	}
+
+	call := APICall{
+		Action:    req.GetAction(),
+		Region:    req.GetRegion(),
+		ProjectID: req.GetProjectId(),
+	}
+	if resp != nil {
+		call.RequestID = resp.GetRequestUUID()
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+	c.observer(call)
+
+	return resp, err
+}
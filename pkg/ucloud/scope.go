@@ -0,0 +1,163 @@
+package ucloud
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+	"github.com/ucloud/ucloud-sdk-go/services/uhost"
+)
+
+// Scope restricts the instances the server can see or act on.
+// A nil scope contains every instance.
+type Scope struct {
+	tags []string
+	ids  map[string]bool
+}
+
+// NewScope creates the scope described by the configuration, reading the
+// instance ID allow-list file if one is set. It returns nil when the scope is unrestricted.
+func NewScope(cfg config.ScopeConfig) (*Scope, error) {
+	if len(cfg.Tags) == 0 && len(cfg.InstanceIDs) == 0 && cfg.InstanceIDsFile == "" {
+		return nil, nil
+	}
+
+	s := &Scope{tags: cfg.Tags, ids: make(map[string]bool)}
+	for _, id := range cfg.InstanceIDs {
+		s.ids[id] = true
+	}
+
+	if cfg.InstanceIDsFile != "" {
+		ids, err := readIDFile(cfg.InstanceIDsFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			s.ids[id] = true
+		}
+	}
+
+	return s, nil
+}
+
+// readIDFile reads one ID per line, skipping blank lines and # comments
+func readIDFile(filename string) ([]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open instance ID file: %v", err)
+	}
+	defer file.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line != "" {
+			ids = append(ids, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read instance ID file: %v", err)
+	}
+	return ids, nil
+}
+
+// Contains reports whether an instance is in scope
+func (s *Scope) Contains(instance *uhost.UHostInstanceSet) bool {
+	return s.ContainsResource(instance.UHostId, instance.Tag)
+}
+
+// ContainsResource reports whether a resource is in scope, either because its tag
+// matches one of the scope's tags or because its ID is allow-listed
+func (s *Scope) ContainsResource(id, tag string) bool {
+	if s == nil {
+		return true
+	}
+	if s.ids[id] {
+		return true
+	}
+	for _, pattern := range s.tags {
+		if matched, _ := path.Match(pattern, tag); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeCheck decides whether resources attached to instances are in the client's
+// scope. Such a resource is in scope if its own ID or tag is, or if an instance it
+// is attached to is. The instances in scope are listed at most once per check.
+type scopeCheck struct {
+	client    *UCloudClient
+	instances map[string]bool
+	networks  map[string]bool
+}
+
+// newScopeCheck returns a scope check for resources looked up by one tool call
+func (c *UCloudClient) newScopeCheck() *scopeCheck {
+	return &scopeCheck{client: c}
+}
+
+// contains reports whether a resource, or any of the instances it is attached to, is in scope
+func (s *scopeCheck) contains(id, tag string, instanceIDs ...string) (bool, error) {
+	if s.client.scope.ContainsResource(id, tag) {
+		return true, nil
+	}
+	for _, instanceID := range instanceIDs {
+		if instanceID == "" {
+			continue
+		}
+		if err := s.load(); err != nil {
+			return false, err
+		}
+		if s.instances[instanceID] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// containsNetwork reports whether a VPC or subnet is in scope, either by its own
+// ID or tag or because an instance in scope is placed in it
+func (s *scopeCheck) containsNetwork(id, tag string) (bool, error) {
+	if s.client.scope.ContainsResource(id, tag) {
+		return true, nil
+	}
+	if err := s.load(); err != nil {
+		return false, err
+	}
+	return s.networks[id], nil
+}
+
+// load lists the instances in scope and the networks they are placed in, once
+func (s *scopeCheck) load() error {
+	if s.instances != nil {
+		return nil
+	}
+
+	// ListInstances only returns instances in scope
+	instances, err := s.client.ListInstances()
+	if err != nil {
+		return err
+	}
+	s.instances = make(map[string]bool)
+	s.networks = make(map[string]bool)
+	for _, instance := range instances {
+		s.instances[instance.UHostId] = true
+		for _, ip := range instance.IPSet {
+			if ip.VPCId != "" {
+				s.networks[ip.VPCId] = true
+			}
+			if ip.SubnetId != "" {
+				s.networks[ip.SubnetId] = true
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package ucloud
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ucloud/ucloud-mcp-server/pkg/config"
+)
+
+func TestScopeContainsResource(t *testing.T) {
+	idFile := filepath.Join(t.TempDir(), "ids.txt")
+	content := "# hosts owned by the web team\nuhost-file1\n\n  uhost-file2  # canary\n"
+	if err := os.WriteFile(idFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	scope, err := NewScope(config.ScopeConfig{
+		Tags:            []string{"web", "db-*"},
+		InstanceIDs:     []string{"uhost-listed"},
+		InstanceIDsFile: idFile,
+	})
+	if err != nil {
+		t.Fatalf("NewScope: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		tag  string
+		want bool
+	}{
+		{"matching tag", "uhost-a", "web", true},
+		{"tag glob", "uhost-a", "db-mysql", true},
+		{"other tag", "uhost-a", "batch", false},
+		{"empty tag", "uhost-a", "", false},
+		{"listed ID", "uhost-listed", "batch", true},
+		{"ID from file", "uhost-file1", "", true},
+		{"ID from file with comment", "uhost-file2", "", true},
+		{"comment is not an ID", "canary", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scope.ContainsResource(tt.id, tt.tag); got != tt.want {
+				t.Errorf("ContainsResource(%q, %q) = %v, want %v", tt.id, tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNilScopeContainsEverything(t *testing.T) {
+	scope, err := NewScope(config.ScopeConfig{})
+	if err != nil {
+		t.Fatalf("NewScope: %v", err)
+	}
+	if scope != nil {
+		t.Fatalf("NewScope of an empty config = %+v, want nil", scope)
+	}
+	if !scope.ContainsResource("uhost-a", "batch") {
+		t.Errorf("a nil scope must contain every resource")
+	}
+}
+
+func TestNewScopeMissingFile(t *testing.T) {
+	_, err := NewScope(config.ScopeConfig{InstanceIDsFile: filepath.Join(t.TempDir(), "missing.txt")})
+	if err == nil {
+		t.Errorf("NewScope with a missing ID file should fail")
+	}
+}
+
+func TestScopeCheckAttachedResources(t *testing.T) {
+	scope, err := NewScope(config.ScopeConfig{InstanceIDs: []string{"uhost-a"}})
+	if err != nil {
+		t.Fatalf("NewScope: %v", err)
+	}
+	// Preloaded, so the check does not list instances
+	check := &scopeCheck{
+		client:    &UCloudClient{scope: scope},
+		instances: map[string]bool{"uhost-a": true},
+		networks:  map[string]bool{"uvnet-a": true, "subnet-a": true},
+	}
+
+	tests := []struct {
+		name      string
+		id        string
+		instances []string
+		want      bool
+	}{
+		{"attached to instance in scope", "bsm-1", []string{"uhost-a"}, true},
+		{"attached to other instance", "bsm-2", []string{"uhost-b"}, false},
+		{"one of several instances in scope", "ulb-1", []string{"uhost-b", "uhost-a"}, true},
+		{"unattached", "eip-1", nil, false},
+		{"unattached with empty instance", "eip-2", []string{""}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := check.contains(tt.id, "", tt.instances...)
+			if err != nil {
+				t.Fatalf("contains: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("contains(%q, %v) = %v, want %v", tt.id, tt.instances, got, tt.want)
+			}
+		})
+	}
+
+	for id, want := range map[string]bool{"uvnet-a": true, "subnet-a": true, "uvnet-b": false} {
+		if got, err := check.containsNetwork(id, ""); err != nil || got != want {
+			t.Errorf("containsNetwork(%q) = %v, %v, want %v", id, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package ucloud
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ucloud/ucloud-sdk-go/services/udb"
+	"github.com/ucloud/ucloud-sdk-go/ucloud"
+)
+
+const (
+	// udbClassSQL is the UDB class of MySQL instances
+	udbClassSQL = "SQL"
+
+	// udbResourceType is the monitoring resource type of UDB instances
+	udbResourceType = "udb"
+)
+
+// ListDatabases gets the UDB MySQL instances in the client's scope
+func (c *UCloudClient) ListDatabases() ([]udb.UDBInstanceSet, error) {
+	var allDatabases []udb.UDBInstanceSet
+	limit := 100
+	offset := 0
+
+	for {
+		req := c.UDBClient.NewDescribeUDBInstanceRequest()
+		req.ClassType = ucloud.String(udbClassSQL)
+		req.Limit = &limit
+		req.Offset = &offset
+
+		resp, err := c.UDBClient.DescribeUDBInstance(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list databases: %v", err)
+		}
+
+		for _, db := range resp.DataSet {
+			if c.scope.ContainsResource(db.DBId, db.Tag) {
+				allDatabases = append(allDatabases, db)
+			}
+		}
+
+		// If the number of databases is less than limit, we've got all data
+		if len(resp.DataSet) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+	}
+
+	return allDatabases, nil
+}
+
+// DescribeDatabase gets a UDB MySQL instance with its replicas.
+// Databases outside the client's scope and other UDB classes are reported as not found.
+func (c *UCloudClient) DescribeDatabase(dbID string) (*udb.UDBInstanceSet, error) {
+	req := c.UDBClient.NewDescribeUDBInstanceRequest()
+	req.ClassType = ucloud.String(udbClassSQL)
+	req.DBId = &dbID
+	req.IncludeSlaves = ucloud.Bool(true)
+
+	resp, err := c.UDBClient.DescribeUDBInstance(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe database %s: %v", dbID, err)
+	}
+
+	// ClassType is ignored when DBId is set, so check the engine of the result
+	if len(resp.DataSet) == 0 || !isMySQL(&resp.DataSet[0]) ||
+		!c.scope.ContainsResource(resp.DataSet[0].DBId, resp.DataSet[0].Tag) {
+		return nil, fmt.Errorf("database %s not found", dbID)
+	}
+
+	return &resp.DataSet[0], nil
+}
+
+// GetDatabaseMetrics retrieves the latest monitoring metrics of a UDB instance
+func (c *UCloudClient) GetDatabaseMetrics(db *udb.UDBInstanceSet) (ResourceMetrics, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database is nil")
+	}
+	return c.GetResourceMetrics(db.Zone, udbResourceType, db.DBId)
+}
+
+// RestartDatabase restarts a UDB instance
+func (c *UCloudClient) RestartDatabase(dbID string) error {
+	req := c.UDBClient.NewRestartUDBInstanceRequest()
+	req.DBId = &dbID
+
+	if _, err := c.UDBClient.RestartUDBInstance(req); err != nil {
+		return fmt.Errorf("failed to restart database %s: %v", dbID, err)
+	}
+	return nil
+}
+
+// CreateDatabaseBackup starts a manual backup of a UDB instance
+func (c *UCloudClient) CreateDatabaseBackup(db *udb.UDBInstanceSet, name string) error {
+	req := c.UDBClient.NewBackupUDBInstanceRequest()
+	req.Zone = &db.Zone
+	req.DBId = &db.DBId
+	req.BackupName = &name
+
+	if _, err := c.UDBClient.BackupUDBInstance(req); err != nil {
+		return fmt.Errorf("failed to back up database %s: %v", db.DBId, err)
+	}
+	return nil
+}
+
+// ListDatabaseBackups gets the backups of a UDB instance, or of all instances if dbID is empty
+func (c *UCloudClient) ListDatabaseBackups(dbID string) ([]udb.UDBBackupSet, error) {
+	var allBackups []udb.UDBBackupSet
+	limit := 100
+	offset := 0
+
+	for {
+		req := c.UDBClient.NewDescribeUDBBackupRequest()
+		req.ClassType = ucloud.String(udbClassSQL)
+		req.Limit = &limit
+		req.Offset = &offset
+		if dbID != "" {
+			req.DBId = &dbID
+		}
+
+		resp, err := c.UDBClient.DescribeUDBBackup(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list database backups: %v", err)
+		}
+		allBackups = append(allBackups, resp.DataSet...)
+
+		// If the number of backups is less than limit, we've got all data
+		if len(resp.DataSet) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+	}
+
+	return allBackups, nil
+}
+
+// DatabaseInfo represents UDB instance information for API response
+type DatabaseInfo struct {
+	ID         string          `json:"id"`
+	ProjectID  string          `json:"project_id,omitempty"`
+	Name       string          `json:"name"`
+	Status     string          `json:"status"`
+	Zone       string          `json:"zone"`
+	Version    string          `json:"version"`
+	SubVersion string          `json:"sub_version,omitempty"`
+	Role       string          `json:"role"`
+	Mode       string          `json:"mode"`
+	MasterID   string          `json:"master_id,omitempty"`
+	IP         string          `json:"ip"`
+	Port       int             `json:"port"`
+	Memory     int             `json:"memory"`
+	DiskSpace  int             `json:"disk_space"`
+	DiskUsed   float64         `json:"disk_used"`
+	DiskUsage  string          `json:"disk_usage"`
+	VPCID      string          `json:"vpc_id,omitempty"`
+	SubnetID   string          `json:"subnet_id,omitempty"`
+	Tag        string          `json:"tag,omitempty"`
+	ReplicaLag *int            `json:"replication_delay_seconds,omitempty"`
+	Replicas   []*DatabaseInfo `json:"replicas,omitempty"`
+	CreateTime string          `json:"create_time"`
+	BackupDate string          `json:"backup_date,omitempty"`
+}
+
+// FormatDatabaseInfo formats UDB instance information for API response
+func FormatDatabaseInfo(db *udb.UDBInstanceSet) *DatabaseInfo {
+	if db == nil {
+		return nil
+	}
+
+	info := &DatabaseInfo{
+		ID:         db.DBId,
+		Name:       db.Name,
+		Status:     db.State,
+		Zone:       db.Zone,
+		Version:    db.DBTypeId,
+		SubVersion: db.DBSubVersion,
+		Role:       db.Role,
+		Mode:       db.InstanceMode,
+		MasterID:   db.SrcDBId,
+		IP:         db.VirtualIP,
+		Port:       db.Port,
+		Memory:     db.MemoryLimit,
+		DiskSpace:  db.DiskSpace,
+		DiskUsed:   db.DiskUsedSize,
+		DiskUsage:  diskUsage(db.DiskUsedSize, db.DiskSpace),
+		VPCID:      db.VPCId,
+		SubnetID:   db.SubnetId,
+		Tag:        db.Tag,
+		CreateTime: time.Unix(int64(db.CreateTime), 0).Format(time.RFC3339),
+		BackupDate: db.BackupDate,
+	}
+	for i := range db.DataSet {
+		info.Replicas = append(info.Replicas, formatReplicaInfo(&db.DataSet[i]))
+	}
+	return info
+}
+
+// formatReplicaInfo formats a UDB replica for API response
+func formatReplicaInfo(replica *udb.UDBSlaveInstanceSet) *DatabaseInfo {
+	lag := replica.ReplicationDelaySeconds
+	return &DatabaseInfo{
+		ID:         replica.DBId,
+		Name:       replica.Name,
+		Status:     replica.State,
+		Zone:       replica.Zone,
+		Version:    replica.DBTypeId,
+		Role:       replica.Role,
+		Mode:       replica.InstanceMode,
+		MasterID:   replica.SrcDBId,
+		IP:         replica.VirtualIP,
+		Port:       replica.Port,
+		Memory:     replica.MemoryLimit,
+		DiskSpace:  replica.DiskSpace,
+		DiskUsed:   replica.DiskUsedSize,
+		DiskUsage:  diskUsage(replica.DiskUsedSize, replica.DiskSpace),
+		VPCID:      replica.VPCId,
+		SubnetID:   replica.SubnetId,
+		Tag:        replica.Tag,
+		ReplicaLag: &lag,
+		CreateTime: time.Unix(int64(replica.CreateTime), 0).Format(time.RFC3339),
+	}
+}
+
+// isMySQL reports whether a UDB instance runs MySQL or Percona, e.g. "mysql-5.7".
+// Instances without a type are assumed to match the class they were listed by.
+func isMySQL(db *udb.UDBInstanceSet) bool {
+	return db.DBTypeId == "" || strings.HasPrefix(db.DBTypeId, "mysql") || strings.HasPrefix(db.DBTypeId, "percona")
+}
+
+// diskUsage formats used GB of a disk of the given size as a percentage
+func diskUsage(used float64, size int) string {
+	if size <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f%%", used/float64(size)*100)
+}
+
+// DatabaseBackupInfo represents UDB backup information for API response
+type DatabaseBackupInfo struct {
+	ID        int    `json:"id"`
+	ProjectID string `json:"project_id,omitempty"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Type      string `json:"type"`
+	DBID      string `json:"db_id"`
+	DBName    string `json:"db_name,omitempty"`
+	Size      int    `json:"size"`
+	Zone      string `json:"zone"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time,omitempty"`
+	Age       string `json:"age"`
+	Error     string `json:"error,omitempty"`
+}
+
+// FormatDatabaseBackupInfo formats UDB backup information for API response
+func FormatDatabaseBackupInfo(backup *udb.UDBBackupSet) *DatabaseBackupInfo {
+	if backup == nil {
+		return nil
+	}
+
+	backupType := "auto"
+	if backup.BackupType == 1 {
+		backupType = "manual"
+	}
+
+	started := time.Unix(int64(backup.BackupTime), 0)
+	info := &DatabaseBackupInfo{
+		ID:        backup.BackupId,
+		Name:      backup.BackupName,
+		Status:    backup.State,
+		Type:      backupType,
+		DBID:      backup.DBId,
+		DBName:    backup.DBName,
+		Size:      backup.BackupSize,
+		Zone:      backup.Zone,
+		StartTime: started.Format(time.RFC3339),
+		Age:       time.Since(started).Round(time.Minute).String(),
+		Error:     backup.ErrorInfo,
+	}
+	if backup.BackupEndTime > 0 {
+		info.EndTime = time.Unix(int64(backup.BackupEndTime), 0).Format(time.RFC3339)
+	}
+	return info
+}
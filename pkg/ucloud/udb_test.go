@@ -0,0 +1,45 @@
+package ucloud
+
+import (
+	"testing"
+
+	"github.com/ucloud/ucloud-sdk-go/services/udb"
+)
+
+func TestDiskUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		used float64
+		size int
+		want string
+	}{
+		{"empty", 0, 100, "0.0%"},
+		{"partly used", 12.5, 50, "25.0%"},
+		{"full", 20, 20, "100.0%"},
+		{"rounded", 1, 3, "33.3%"},
+		{"unknown size", 5, 0, ""},
+		{"negative size", 5, -1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diskUsage(tt.used, tt.size); got != tt.want {
+				t.Errorf("diskUsage(%v, %d) = %q, want %q", tt.used, tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsMySQL(t *testing.T) {
+	for dbType, want := range map[string]bool{
+		"mysql-5.7":       true,
+		"percona-5.6":     true,
+		"":                true,
+		"mongodb-3.6":     false,
+		"postgresql-10.4": false,
+	} {
+		if got := isMySQL(&udb.UDBInstanceSet{DBTypeId: dbType}); got != want {
+			t.Errorf("isMySQL(%q) = %v, want %v", dbType, got, want)
+		}
+	}
+}
@@ -0,0 +1,451 @@
+package ucloud
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ucloud/ucloud-sdk-go/services/udisk"
+)
+
+// Disk states reported by UDisk
+const (
+	DiskStatusAvailable = "Available"
+	DiskStatusInUse     = "InUse"
+)
+
+// Snapshot states reported by UDisk
+const (
+	SnapshotStatusNormal = "Normal"
+	SnapshotStatusFailed = "Failed"
+)
+
+// Polling settings for disk and snapshot state changes
+const (
+	diskPollInterval    = 3 * time.Second
+	diskPollTimeout     = 5 * time.Minute
+	snapshotPollTimeout = 30 * time.Minute
+)
+
+// ListDisks gets the disks in the client's scope, optionally only those attached to a host
+func (c *UCloudClient) ListDisks(hostID string) ([]udisk.UDiskDataSet, error) {
+	var allDisks []udisk.UDiskDataSet
+	limit := 100
+	offset := 0
+	check := c.newScopeCheck()
+
+	for {
+		req := c.UDiskClient.NewDescribeUDiskRequest()
+		req.Limit = &limit
+		req.Offset = &offset
+		if hostID != "" {
+			req.HostId = &hostID
+		}
+
+		resp, err := c.UDiskClient.DescribeUDisk(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list disks: %v", err)
+		}
+
+		for _, disk := range resp.DataSet {
+			inScope, err := check.contains(disk.UDiskId, disk.Tag, disk.HostId)
+			if err != nil {
+				return nil, err
+			}
+			if inScope {
+				allDisks = append(allDisks, disk)
+			}
+		}
+
+		// If the number of disks is less than limit, we've got all data
+		if len(resp.DataSet) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+	}
+
+	return allDisks, nil
+}
+
+// DescribeDisk gets detailed information about a disk.
+// Disks outside the client's scope, and not attached to an instance in scope, are reported as not found.
+func (c *UCloudClient) DescribeDisk(diskID string) (*udisk.UDiskDataSet, error) {
+	disk, err := c.describeDisk(diskID)
+	if err != nil {
+		return nil, err
+	}
+	inScope, err := c.newScopeCheck().contains(disk.UDiskId, disk.Tag, disk.HostId)
+	if err != nil {
+		return nil, err
+	}
+	if !inScope {
+		return nil, fmt.Errorf("disk %s not found", diskID)
+	}
+
+	return disk, nil
+}
+
+// describeDisk looks up a disk without checking the scope
+func (c *UCloudClient) describeDisk(diskID string) (*udisk.UDiskDataSet, error) {
+	req := c.UDiskClient.NewDescribeUDiskRequest()
+	req.UDiskId = &diskID
+
+	resp, err := c.UDiskClient.DescribeUDisk(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe disk %s: %v", diskID, err)
+	}
+
+	if len(resp.DataSet) == 0 {
+		return nil, fmt.Errorf("disk %s not found", diskID)
+	}
+	return &resp.DataSet[0], nil
+}
+
+// DiskSpec describes a disk to create
+type DiskSpec struct {
+	Zone     string
+	Name     string
+	Size     int
+	DiskType string
+	Tag      string
+}
+
+// CreateDisk creates a disk and returns its ID
+func (c *UCloudClient) CreateDisk(spec DiskSpec) (string, error) {
+	req := c.UDiskClient.NewCreateUDiskRequest()
+	req.Zone = &spec.Zone
+	req.Name = &spec.Name
+	req.Size = &spec.Size
+	if spec.DiskType != "" {
+		req.DiskType = &spec.DiskType
+	}
+	if spec.Tag != "" {
+		req.Tag = &spec.Tag
+	}
+
+	resp, err := c.UDiskClient.CreateUDisk(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to create disk: %v", err)
+	}
+
+	if len(resp.UDiskId) == 0 {
+		if c.dryRun {
+			return dryRunID, nil
+		}
+		return "", fmt.Errorf("failed to create disk: response has no disk ID")
+	}
+	return resp.UDiskId[0], nil
+}
+
+// AttachDisk attaches a disk to a host in the disk's zone
+func (c *UCloudClient) AttachDisk(disk *udisk.UDiskDataSet, hostID string) error {
+	req := c.UDiskClient.NewAttachUDiskRequest()
+	req.Zone = &disk.Zone
+	req.UDiskId = &disk.UDiskId
+	req.HostId = &hostID
+
+	if _, err := c.UDiskClient.AttachUDisk(req); err != nil {
+		return fmt.Errorf("failed to attach disk %s to %s: %v", disk.UDiskId, hostID, err)
+	}
+	return nil
+}
+
+// DetachDisk detaches a disk from the host it is attached to
+func (c *UCloudClient) DetachDisk(disk *udisk.UDiskDataSet) error {
+	req := c.UDiskClient.NewDetachUDiskRequest()
+	req.Zone = &disk.Zone
+	req.UDiskId = &disk.UDiskId
+	req.HostId = &disk.HostId
+
+	if _, err := c.UDiskClient.DetachUDisk(req); err != nil {
+		return fmt.Errorf("failed to detach disk %s from %s: %v", disk.UDiskId, disk.HostId, err)
+	}
+	return nil
+}
+
+// ResizeDisk grows a disk to the given size in GB
+func (c *UCloudClient) ResizeDisk(disk *udisk.UDiskDataSet, size int) error {
+	req := c.UDiskClient.NewResizeUDiskRequest()
+	req.Zone = &disk.Zone
+	req.UDiskId = &disk.UDiskId
+	req.Size = &size
+
+	if _, err := c.UDiskClient.ResizeUDisk(req); err != nil {
+		return fmt.Errorf("failed to resize disk %s: %v", disk.UDiskId, err)
+	}
+	return nil
+}
+
+// DeleteDisk deletes a detached disk
+func (c *UCloudClient) DeleteDisk(disk *udisk.UDiskDataSet) error {
+	req := c.UDiskClient.NewDeleteUDiskRequest()
+	req.Zone = &disk.Zone
+	req.UDiskId = &disk.UDiskId
+
+	if _, err := c.UDiskClient.DeleteUDisk(req); err != nil {
+		return fmt.Errorf("failed to delete disk %s: %v", disk.UDiskId, err)
+	}
+	return nil
+}
+
+// WaitForDiskStatus polls a disk until it reaches the status, the timeout expires
+// or the context is cancelled. Dry-run clients return immediately because no change was made.
+// The disk is not checked against the scope again: it was when it was looked up for the
+// change, and detaching it clears the host that may have put it in scope.
+func (c *UCloudClient) WaitForDiskStatus(ctx context.Context, diskID, status string) (*udisk.UDiskDataSet, error) {
+	if c.dryRun {
+		return nil, nil
+	}
+
+	ticker := time.NewTicker(diskPollInterval)
+	defer ticker.Stop()
+
+	deadline := time.Now().Add(diskPollTimeout)
+	for {
+		disk, err := c.describeDisk(diskID)
+		if err != nil {
+			return nil, err
+		}
+		if disk.Status == status {
+			return disk, nil
+		}
+		if time.Now().After(deadline) {
+			return disk, fmt.Errorf("timed out waiting for disk %s to become %s, current status %s",
+				diskID, status, disk.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return disk, fmt.Errorf("stopped waiting for disk %s to become %s: %v", diskID, status, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// DiskInfo represents disk information for API response
+type DiskInfo struct {
+	ID         string `json:"id"`
+	ProjectID  string `json:"project_id,omitempty"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Zone       string `json:"zone"`
+	Size       int    `json:"size"`
+	DiskType   string `json:"disk_type"`
+	IsBoot     bool   `json:"is_boot"`
+	Tag        string `json:"tag,omitempty"`
+	HostID     string `json:"host_id,omitempty"`
+	HostName   string `json:"host_name,omitempty"`
+	DeviceName string `json:"device_name,omitempty"`
+	ChargeType string `json:"charge_type,omitempty"`
+	CreateTime string `json:"create_time,omitempty"`
+}
+
+// FormatDiskInfo formats UDisk information for API response
+func FormatDiskInfo(disk *udisk.UDiskDataSet) *DiskInfo {
+	if disk == nil {
+		return nil
+	}
+
+	return &DiskInfo{
+		ID:         disk.UDiskId,
+		Name:       disk.Name,
+		Status:     disk.Status,
+		Zone:       disk.Zone,
+		Size:       disk.Size,
+		DiskType:   disk.DiskType,
+		IsBoot:     disk.IsBoot == "True",
+		Tag:        disk.Tag,
+		HostID:     disk.HostId,
+		HostName:   disk.HostName,
+		DeviceName: disk.DeviceName,
+		ChargeType: disk.ChargeType,
+		CreateTime: time.Unix(int64(disk.CreateTime), 0).Format(time.RFC3339),
+	}
+}
+
+// SystemDisk returns the boot disk of an instance
+func (c *UCloudClient) SystemDisk(hostID string) (*udisk.UDiskDataSet, error) {
+	disks, err := c.ListDisks(hostID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range disks {
+		if disks[i].IsBoot == "True" {
+			return &disks[i], nil
+		}
+	}
+	return nil, fmt.Errorf("instance %s has no cloud system disk", hostID)
+}
+
+// ListSnapshots gets the snapshots of a disk, or of all disks if diskID is empty
+func (c *UCloudClient) ListSnapshots(diskID string) ([]udisk.UDiskSnapshotSet, error) {
+	var allSnapshots []udisk.UDiskSnapshotSet
+	limit := 100
+	offset := 0
+
+	for {
+		req := c.UDiskClient.NewDescribeUDiskSnapshotRequest()
+		req.Limit = &limit
+		req.Offset = &offset
+		if diskID != "" {
+			req.UDiskId = &diskID
+		}
+
+		resp, err := c.UDiskClient.DescribeUDiskSnapshot(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list snapshots: %v", err)
+		}
+		allSnapshots = append(allSnapshots, resp.DataSet...)
+
+		// If the number of snapshots is less than limit, we've got all data
+		if len(resp.DataSet) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+	}
+
+	return allSnapshots, nil
+}
+
+// DescribeSnapshot gets detailed information about a snapshot
+func (c *UCloudClient) DescribeSnapshot(snapshotID string) (*udisk.UDiskSnapshotSet, error) {
+	req := c.UDiskClient.NewDescribeUDiskSnapshotRequest()
+	req.SnapshotId = &snapshotID
+
+	resp, err := c.UDiskClient.DescribeUDiskSnapshot(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe snapshot %s: %v", snapshotID, err)
+	}
+
+	if len(resp.DataSet) == 0 {
+		return nil, fmt.Errorf("snapshot %s not found", snapshotID)
+	}
+
+	return &resp.DataSet[0], nil
+}
+
+// CreateSnapshot snapshots a disk and returns the snapshot ID
+func (c *UCloudClient) CreateSnapshot(disk *udisk.UDiskDataSet, name, comment string) (string, error) {
+	req := c.UDiskClient.NewCreateUDiskSnapshotRequest()
+	req.Zone = &disk.Zone
+	req.UDiskId = &disk.UDiskId
+	req.Name = &name
+	if comment != "" {
+		req.Comment = &comment
+	}
+
+	resp, err := c.UDiskClient.CreateUDiskSnapshot(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to snapshot disk %s: %v", disk.UDiskId, err)
+	}
+
+	if len(resp.SnapshotId) == 0 {
+		if c.dryRun {
+			return dryRunID, nil
+		}
+		return "", fmt.Errorf("failed to snapshot disk %s: response has no snapshot ID", disk.UDiskId)
+	}
+	return resp.SnapshotId[0], nil
+}
+
+// WaitForSnapshot polls a snapshot until it is usable, it fails, the timeout
+// expires or the context is cancelled. Dry-run clients return immediately because no snapshot was made.
+func (c *UCloudClient) WaitForSnapshot(ctx context.Context, snapshotID string) error {
+	if c.dryRun {
+		return nil
+	}
+
+	ticker := time.NewTicker(diskPollInterval)
+	defer ticker.Stop()
+
+	deadline := time.Now().Add(snapshotPollTimeout)
+	for {
+		snapshot, err := c.DescribeSnapshot(snapshotID)
+		if err != nil {
+			return err
+		}
+		switch snapshot.Status {
+		case SnapshotStatusNormal:
+			return nil
+		case SnapshotStatusFailed:
+			return fmt.Errorf("snapshot %s failed", snapshotID)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for snapshot %s to complete, current status %s",
+				snapshotID, snapshot.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for snapshot %s: %v", snapshotID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// RestoreSnapshot rolls a snapshot's disk back to the snapshot
+func (c *UCloudClient) RestoreSnapshot(snapshot *udisk.UDiskSnapshotSet) error {
+	req := c.UDiskClient.NewRestoreUDiskRequest()
+	req.Zone = &snapshot.Zone
+	req.UDiskId = &snapshot.UDiskId
+	req.SnapshotId = &snapshot.SnapshotId
+
+	if _, err := c.UDiskClient.RestoreUDisk(req); err != nil {
+		return fmt.Errorf("failed to restore disk %s from snapshot %s: %v", snapshot.UDiskId, snapshot.SnapshotId, err)
+	}
+	return nil
+}
+
+// DeleteSnapshot deletes a snapshot
+func (c *UCloudClient) DeleteSnapshot(snapshot *udisk.UDiskSnapshotSet) error {
+	req := c.UDiskClient.NewDeleteUDiskSnapshotRequest()
+	req.Zone = &snapshot.Zone
+	req.SnapshotId = &snapshot.SnapshotId
+
+	if _, err := c.UDiskClient.DeleteUDiskSnapshot(req); err != nil {
+		return fmt.Errorf("failed to delete snapshot %s: %v", snapshot.SnapshotId, err)
+	}
+	return nil
+}
+
+// SnapshotInfo represents snapshot information for API response
+type SnapshotInfo struct {
+	ID         string `json:"id"`
+	ProjectID  string `json:"project_id,omitempty"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Zone       string `json:"zone"`
+	Size       int    `json:"size"`
+	DiskID     string `json:"disk_id"`
+	DiskName   string `json:"disk_name,omitempty"`
+	InstanceID string `json:"instance_id,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+	CreateTime string `json:"create_time"`
+	Age        string `json:"age"`
+}
+
+// FormatSnapshotInfo formats UDisk snapshot information for API response
+func FormatSnapshotInfo(snapshot *udisk.UDiskSnapshotSet) *SnapshotInfo {
+	if snapshot == nil {
+		return nil
+	}
+
+	created := time.Unix(int64(snapshot.CreateTime), 0)
+	return &SnapshotInfo{
+		ID:         snapshot.SnapshotId,
+		Name:       snapshot.Name,
+		Status:     snapshot.Status,
+		Zone:       snapshot.Zone,
+		Size:       snapshot.Size,
+		DiskID:     snapshot.UDiskId,
+		DiskName:   snapshot.UDiskName,
+		InstanceID: snapshot.UHostId,
+		Comment:    snapshot.Comment,
+		CreateTime: created.Format(time.RFC3339),
+		Age:        time.Since(created).Round(time.Minute).String(),
+	}
+}
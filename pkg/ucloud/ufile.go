@@ -0,0 +1,390 @@
+package ucloud
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ucloud/ucloud-sdk-go/services/ufile"
+)
+
+// ListBuckets gets the US3 (UFile) buckets in the client's scope
+func (c *UCloudClient) ListBuckets() ([]ufile.UFileBucketSet, error) {
+	var allBuckets []ufile.UFileBucketSet
+	limit := 100
+	offset := 0
+
+	for {
+		req := c.UFileClient.NewDescribeBucketRequest()
+		req.Limit = &limit
+		req.Offset = &offset
+
+		resp, err := c.UFileClient.DescribeBucket(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list buckets: %v", err)
+		}
+
+		for _, bucket := range resp.DataSet {
+			if c.scope.ContainsResource(bucket.BucketName, bucket.Tag) {
+				allBuckets = append(allBuckets, bucket)
+			}
+		}
+
+		// If the number of buckets is less than limit, we've got all data
+		if len(resp.DataSet) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+	}
+
+	return allBuckets, nil
+}
+
+// DescribeBucket gets a US3 bucket by name.
+// Buckets outside the client's scope are reported as not found.
+func (c *UCloudClient) DescribeBucket(name string) (*ufile.UFileBucketSet, error) {
+	req := c.UFileClient.NewDescribeBucketRequest()
+	req.BucketName = &name
+
+	resp, err := c.UFileClient.DescribeBucket(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe bucket %s: %v", name, err)
+	}
+
+	if len(resp.DataSet) == 0 || !c.scope.ContainsResource(resp.DataSet[0].BucketName, resp.DataSet[0].Tag) {
+		return nil, fmt.Errorf("bucket %s not found", name)
+	}
+
+	return &resp.DataSet[0], nil
+}
+
+// ObjectInfo represents a US3 object for API response
+type ObjectInfo struct {
+	Key          string `json:"key"`
+	Size         int64  `json:"size"`
+	ContentType  string `json:"content_type,omitempty"`
+	ETag         string `json:"etag,omitempty"`
+	StorageClass string `json:"storage_class,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// ObjectList is a page of objects and common prefixes in a bucket
+type ObjectList struct {
+	Bucket         string        `json:"bucket"`
+	Prefix         string        `json:"prefix,omitempty"`
+	Delimiter      string        `json:"delimiter,omitempty"`
+	Objects        []*ObjectInfo `json:"objects"`
+	CommonPrefixes []string      `json:"common_prefixes,omitempty"`
+	IsTruncated    bool          `json:"is_truncated"`
+	NextMarker     string        `json:"next_marker,omitempty"`
+}
+
+// ListObjects lists up to limit objects of a bucket whose keys start with prefix and follow marker.
+// With a delimiter, keys sharing the part up to the next delimiter are grouped into common prefixes.
+func (c *UCloudClient) ListObjects(bucket *ufile.UFileBucketSet, prefix, delimiter, marker string, limit int) (*ObjectList, error) {
+	query := url.Values{}
+	query.Set("prefix", prefix)
+	query.Set("marker", marker)
+	query.Set("max-keys", strconv.Itoa(limit))
+	if delimiter != "" {
+		query.Set("delimiter", delimiter)
+	}
+
+	resp, err := c.objectRequest(bucket, http.MethodGet, "", "listobjects&"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects in bucket %s: %v", bucket.BucketName, err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		IsTruncated bool   `json:"IsTruncated"`
+		NextMarker  string `json:"NextMarker"`
+		Contents    []struct {
+			Key          string      `json:"Key"`
+			MimeType     string      `json:"MimeType"`
+			LastModified int64       `json:"LastModified"`
+			Etag         string      `json:"Etag"`
+			Size         json.Number `json:"Size"`
+			StorageClass string      `json:"StorageClass"`
+		} `json:"Contents"`
+		CommonPrefixes []struct {
+			Prefix string `json:"Prefix"`
+		} `json:"CommonPrefixes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse object list of bucket %s: %v", bucket.BucketName, err)
+	}
+
+	list := &ObjectList{
+		Bucket:      bucket.BucketName,
+		Prefix:      prefix,
+		Delimiter:   delimiter,
+		Objects:     []*ObjectInfo{},
+		IsTruncated: result.IsTruncated,
+		NextMarker:  result.NextMarker,
+	}
+	for _, object := range result.Contents {
+		size, _ := object.Size.Int64()
+		list.Objects = append(list.Objects, &ObjectInfo{
+			Key:          object.Key,
+			Size:         size,
+			ContentType:  object.MimeType,
+			ETag:         object.Etag,
+			StorageClass: object.StorageClass,
+			LastModified: time.Unix(object.LastModified, 0).Format(time.RFC3339),
+		})
+	}
+	for _, p := range result.CommonPrefixes {
+		list.CommonPrefixes = append(list.CommonPrefixes, p.Prefix)
+	}
+	return list, nil
+}
+
+// HeadObject gets the metadata of an object
+func (c *UCloudClient) HeadObject(bucket *ufile.UFileBucketSet, key string) (*ObjectInfo, error) {
+	resp, err := c.objectRequest(bucket, http.MethodHead, key, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object %s: %v", key, err)
+	}
+	resp.Body.Close()
+
+	return objectInfoFromHeader(key, resp.Header, resp.ContentLength), nil
+}
+
+// GetObject reads at most maxBytes from the start of an object.
+// It reports whether the object is longer than what was read.
+func (c *UCloudClient) GetObject(bucket *ufile.UFileBucketSet, key string, maxBytes int64) ([]byte, bool, error) {
+	header := http.Header{}
+	header.Set("Range", fmt.Sprintf("bytes=0-%d", maxBytes-1))
+
+	resp, err := c.objectRequest(bucket, http.MethodGet, key, "", header)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get object %s: %v", key, err)
+	}
+	defer resp.Body.Close()
+
+	// Read one extra byte to tell whether the endpoint ignored the range
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read object %s: %v", key, err)
+	}
+
+	truncated := int64(len(data)) > maxBytes
+	if truncated {
+		data = data[:maxBytes]
+	}
+	if total := contentRangeSize(resp.Header.Get("Content-Range")); total > int64(len(data)) {
+		truncated = true
+	}
+	return data, truncated, nil
+}
+
+// PresignObjectURL returns a URL that allows downloading an object without credentials until it
+// expires. URLs signed with temporary keys expire with the keys at the latest.
+func (c *UCloudClient) PresignObjectURL(bucket *ufile.UFileBucketSet, key string, expiry time.Duration) (string, time.Time, error) {
+	endpoint, err := bucketEndpoint(bucket)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expires := time.Now().Add(expiry).Truncate(time.Second)
+	if !c.expires.IsZero() && c.expires.Before(expires) {
+		expires = c.expires.Truncate(time.Second)
+	}
+	expiresParam := strconv.FormatInt(expires.Unix(), 10)
+	signature := c.signObjectRequest(http.MethodGet, bucket.BucketName, key, expiresParam, nil)
+
+	query := url.Values{}
+	query.Set("UCloudPublicKey", c.credential.PublicKey)
+	query.Set("Signature", signature)
+	query.Set("Expires", expiresParam)
+	if c.credential.SecurityToken != "" {
+		query.Set("SecurityToken", c.credential.SecurityToken)
+	}
+	return endpoint + objectPath(key) + "?" + query.Encode(), expires, nil
+}
+
+// objectRequest sends a signed request to the bucket's endpoint and returns the
+// response if it succeeded. rawQuery is appended to the URL as is.
+func (c *UCloudClient) objectRequest(bucket *ufile.UFileBucketSet, method, key, rawQuery string, header http.Header) (*http.Response, error) {
+	endpoint, err := bucketEndpoint(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	target := endpoint + objectPath(key)
+	if rawQuery != "" {
+		target += "?" + rawQuery
+	}
+	req, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	date := time.Now().UTC().Format(http.TimeFormat)
+	req.Header.Set("Date", date)
+	req.Header.Set("User-Agent", c.config.UserAgent)
+	if c.credential.SecurityToken != "" {
+		req.Header.Set("SecurityToken", c.credential.SecurityToken)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("UCloud %s:%s", c.credential.PublicKey,
+		c.signObjectRequest(method, bucket.BucketName, key, date, req.Header)))
+
+	httpClient := &http.Client{Transport: c.transport, Timeout: c.config.Timeout}
+	resp, err := httpClient.Do(req)
+
+	call := APICall{Action: objectAction(method, key), Region: bucket.Region, ProjectID: c.ProjectID()}
+	if err != nil {
+		call.Error = err.Error()
+		c.reportCall(call)
+		return nil, err
+	}
+	call.RequestID = resp.Header.Get("X-SessionId")
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		var body struct {
+			RetCode int    `json:"RetCode"`
+			ErrMsg  string `json:"ErrMsg"`
+		}
+		err = fmt.Errorf("US3 returned %s", resp.Status)
+		if json.NewDecoder(resp.Body).Decode(&body) == nil && body.ErrMsg != "" {
+			err = fmt.Errorf("US3 returned %s: %s", resp.Status, body.ErrMsg)
+		}
+		call.Error = err.Error()
+		c.reportCall(call)
+		return nil, err
+	}
+
+	c.reportCall(call)
+	return resp, nil
+}
+
+// reportCall reports a call that does not go through an SDK client to the observer
+func (c *UCloudClient) reportCall(call APICall) {
+	if c.observer != nil {
+		c.observer(call)
+	}
+}
+
+// signObjectRequest computes the US3 signature of a request. date is the Date
+// header, or the expiry time of a presigned URL.
+func (c *UCloudClient) signObjectRequest(method, bucket, key, date string, header http.Header) string {
+	stringToSign := strings.Join([]string{
+		method,
+		header.Get("Content-MD5"),
+		header.Get("Content-Type"),
+		date,
+	}, "\n") + "\n" + canonicalUCloudHeaders(header) + "/" + bucket + "/" + key
+
+	mac := hmac.New(sha1.New, []byte(c.credential.PrivateKey))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// canonicalUCloudHeaders returns the X-UCloud-* headers of a request in the form they are signed
+func canonicalUCloudHeaders(header http.Header) string {
+	var lines []string
+	for name, values := range header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-ucloud-") {
+			lines = append(lines, lower+":"+strings.Join(values, ",")+"\n")
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "")
+}
+
+// objectAction names an object request in the API calls reported to the observer
+func objectAction(method, key string) string {
+	switch {
+	case method == http.MethodHead:
+		return "US3:HeadObject"
+	case key == "":
+		return "US3:ListObjects"
+	default:
+		return "US3:GetObject"
+	}
+}
+
+// bucketEndpoint returns the base URL of a bucket's source domain
+func bucketEndpoint(bucket *ufile.UFileBucketSet) (string, error) {
+	if len(bucket.Domain.Src) == 0 {
+		return "", fmt.Errorf("bucket %s has no source domain", bucket.BucketName)
+	}
+	return "https://" + bucket.Domain.Src[0], nil
+}
+
+// objectPath returns the escaped URL path of an object key
+func objectPath(key string) string {
+	return (&url.URL{Path: "/" + key}).EscapedPath()
+}
+
+// objectInfoFromHeader builds object metadata from the headers of a HEAD or GET response
+func objectInfoFromHeader(key string, header http.Header, size int64) *ObjectInfo {
+	return &ObjectInfo{
+		Key:          key,
+		Size:         size,
+		ContentType:  header.Get("Content-Type"),
+		ETag:         strings.Trim(header.Get("ETag"), `"`),
+		StorageClass: header.Get("X-Ufile-Storage-Class"),
+		LastModified: header.Get("Last-Modified"),
+	}
+}
+
+// contentRangeSize returns the total size from a Content-Range header, or -1 if it is missing
+func contentRangeSize(contentRange string) int64 {
+	i := strings.LastIndex(contentRange, "/")
+	if i < 0 {
+		return -1
+	}
+	size, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
+// BucketInfo represents US3 bucket information for API response
+type BucketInfo struct {
+	ID         string   `json:"id"`
+	ProjectID  string   `json:"project_id,omitempty"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Region     string   `json:"region"`
+	Domains    []string `json:"domains"`
+	CDNDomains []string `json:"cdn_domains,omitempty"`
+	Tag        string   `json:"tag,omitempty"`
+	CreateTime string   `json:"create_time"`
+}
+
+// FormatBucketInfo formats US3 bucket information for API response
+func FormatBucketInfo(bucket *ufile.UFileBucketSet) *BucketInfo {
+	if bucket == nil {
+		return nil
+	}
+
+	return &BucketInfo{
+		ID:         bucket.BucketId,
+		Name:       bucket.BucketName,
+		Type:       bucket.Type,
+		Region:     bucket.Region,
+		Domains:    append(append([]string{}, bucket.Domain.Src...), bucket.Domain.CustomSrc...),
+		CDNDomains: append(append([]string{}, bucket.Domain.Cdn...), bucket.Domain.CustomCdn...),
+		Tag:        bucket.Tag,
+		CreateTime: time.Unix(int64(bucket.CreateTime), 0).Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,142 @@
+package ucloud
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ucloud/ucloud-sdk-go/services/ufile"
+	"github.com/ucloud/ucloud-sdk-go/ucloud/auth"
+)
+
+// sign computes the expected signature of a string to sign
+func sign(privateKey, stringToSign string) string {
+	mac := hmac.New(sha1.New, []byte(privateKey))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestSignObjectRequest(t *testing.T) {
+	c := &UCloudClient{credential: auth.Credential{PublicKey: "public", PrivateKey: "private"}}
+	date := "Mon, 02 Jan 2006 15:04:05 GMT"
+
+	tests := []struct {
+		name         string
+		method       string
+		key          string
+		header       http.Header
+		stringToSign string
+	}{
+		{
+			name:         "presigned GET",
+			method:       http.MethodGet,
+			key:          "logs/app.log",
+			stringToSign: "GET\n\n\n" + date + "\n/bucket/logs/app.log",
+		},
+		{
+			name:   "content headers",
+			method: http.MethodHead,
+			key:    "a.txt",
+			header: http.Header{
+				"Content-Md5":  {"md5"},
+				"Content-Type": {"text/plain"},
+			},
+			stringToSign: "HEAD\nmd5\ntext/plain\n" + date + "\n/bucket/a.txt",
+		},
+		{
+			name:   "sorted UCloud headers",
+			method: http.MethodGet,
+			key:    "a.txt",
+			header: http.Header{
+				"X-Ucloud-B": {"2"},
+				"X-Ucloud-A": {"1", "3"},
+				"Range":      {"bytes=0-9"},
+			},
+			stringToSign: "GET\n\n\n" + date + "\nx-ucloud-a:1,3\nx-ucloud-b:2\n/bucket/a.txt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.signObjectRequest(tt.method, "bucket", tt.key, date, tt.header)
+			if want := sign("private", tt.stringToSign); got != want {
+				t.Errorf("signObjectRequest = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestPresignObjectURL(t *testing.T) {
+	bucket := &ufile.UFileBucketSet{BucketName: "bucket"}
+	bucket.Domain.Src = []string{"bucket.cn-bj.ufileos.com"}
+
+	tests := []struct {
+		name          string
+		securityToken string
+		keyExpires    time.Time
+		capped        bool
+	}{
+		{name: "permanent keys"},
+		{name: "temporary keys", securityToken: "token", keyExpires: time.Now().Add(time.Minute), capped: true},
+		{name: "temporary keys outliving the URL", securityToken: "token", keyExpires: time.Now().Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &UCloudClient{
+				credential: auth.Credential{PublicKey: "public", PrivateKey: "private", SecurityToken: tt.securityToken},
+				expires:    tt.keyExpires,
+			}
+			presigned, expires, err := c.PresignObjectURL(bucket, "dir/a b.txt", 15*time.Minute)
+			if err != nil {
+				t.Fatalf("PresignObjectURL: %v", err)
+			}
+
+			u, err := url.Parse(presigned)
+			if err != nil {
+				t.Fatalf("invalid URL %q: %v", presigned, err)
+			}
+			if u.Host != "bucket.cn-bj.ufileos.com" || u.EscapedPath() != "/dir/a%20b.txt" {
+				t.Errorf("URL = %q, want the object on the bucket's domain", presigned)
+			}
+			query := u.Query()
+			if got := query.Get("SecurityToken"); got != tt.securityToken {
+				t.Errorf("SecurityToken = %q, want %q", got, tt.securityToken)
+			}
+			if tt.capped && !expires.Equal(tt.keyExpires.Truncate(time.Second)) {
+				t.Errorf("expires = %v, want the key expiry %v", expires, tt.keyExpires)
+			}
+			if !tt.capped && expires.Sub(time.Now()) < 14*time.Minute {
+				t.Errorf("expires = %v, want about 15 minutes from now", expires)
+			}
+			want := c.signObjectRequest(http.MethodGet, "bucket", "dir/a b.txt", query.Get("Expires"), nil)
+			if got := query.Get("Signature"); got != want {
+				t.Errorf("Signature = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestContentRangeSize(t *testing.T) {
+	tests := []struct {
+		contentRange string
+		want         int64
+	}{
+		{"bytes 0-1023/4096", 4096},
+		{"bytes 0-0/1", 1},
+		{"bytes */4096", 4096},
+		{"bytes 0-1023/*", -1},
+		{"", -1},
+		{"garbage", -1},
+	}
+
+	for _, tt := range tests {
+		if got := contentRangeSize(tt.contentRange); got != tt.want {
+			t.Errorf("contentRangeSize(%q) = %d, want %d", tt.contentRange, got, tt.want)
+		}
+	}
+}
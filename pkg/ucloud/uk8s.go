@@ -0,0 +1,321 @@
+package ucloud
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ucloud/ucloud-sdk-go/services/uhost"
+	"github.com/ucloud/ucloud-sdk-go/services/uk8s"
+)
+
+// K8SNodeRoleMaster is the role of UK8S master nodes
+const K8SNodeRoleMaster = "master"
+
+// ListK8SClusters gets the UK8S clusters in the client's scope. Clusters have no
+// business group tag, so a cluster is in scope if its ID is allow-listed or any
+// of its nodes is an instance in scope.
+func (c *UCloudClient) ListK8SClusters() ([]uk8s.ClusterSet, error) {
+	clusters, err := c.listK8SClusters()
+	if err != nil || c.scope == nil {
+		return clusters, err
+	}
+
+	instances, err := c.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+	scoped, err := c.k8sClustersWithNodes(clusters, instances)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]uk8s.ClusterSet, len(scoped))
+	for i := range scoped {
+		result[i] = scoped[i].ClusterSet
+	}
+	return result, nil
+}
+
+// K8SCluster is a UK8S cluster with its nodes in the client's scope
+type K8SCluster struct {
+	uk8s.ClusterSet
+	Nodes []uk8s.NodeInfoV2
+}
+
+// ListK8SClustersWithNodes gets the UK8S clusters in the client's scope with their
+// nodes in scope. instances are the instances in scope as returned by ListInstances,
+// so callers that need them as well list them only once.
+func (c *UCloudClient) ListK8SClustersWithNodes(instances []uhost.UHostInstanceSet) ([]K8SCluster, error) {
+	clusters, err := c.listK8SClusters()
+	if err != nil {
+		return nil, err
+	}
+	return c.k8sClustersWithNodes(clusters, instances)
+}
+
+// k8sClustersWithNodes lists the nodes of each cluster and keeps the clusters in scope
+func (c *UCloudClient) k8sClustersWithNodes(clusters []uk8s.ClusterSet, instances []uhost.UHostInstanceSet) ([]K8SCluster, error) {
+	instanceIDs := instanceIDSet(instances)
+	var scoped []K8SCluster
+	for _, cluster := range clusters {
+		nodes, inScope, err := c.k8sNodesInScope(cluster.ClusterId, instanceIDs)
+		if err != nil {
+			return nil, err
+		}
+		if inScope {
+			scoped = append(scoped, K8SCluster{ClusterSet: cluster, Nodes: nodes})
+		}
+	}
+	return scoped, nil
+}
+
+// listK8SClusters gets all UK8S clusters, regardless of the scope
+func (c *UCloudClient) listK8SClusters() ([]uk8s.ClusterSet, error) {
+	var allClusters []uk8s.ClusterSet
+	limit := 100
+	offset := 0
+
+	for {
+		req := c.UK8SClient.NewListUK8SClusterV2Request()
+		req.Limit = &limit
+		req.Offset = &offset
+
+		resp, err := c.UK8SClient.ListUK8SClusterV2(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list k8s clusters: %v", err)
+		}
+		allClusters = append(allClusters, resp.ClusterSet...)
+
+		// If the number of clusters is less than limit, we've got all data
+		if len(resp.ClusterSet) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+	}
+
+	return allClusters, nil
+}
+
+// DescribeK8SCluster gets a UK8S cluster with its nodes in the client's scope.
+// instances are the instances in scope as returned by ListInstances.
+// Clusters outside the client's scope are reported as not found.
+func (c *UCloudClient) DescribeK8SCluster(clusterID string, instances []uhost.UHostInstanceSet) (*uk8s.DescribeUK8SClusterResponse, []uk8s.NodeInfoV2, error) {
+	req := c.UK8SClient.NewDescribeUK8SClusterRequest()
+	req.ClusterId = &clusterID
+
+	resp, err := c.UK8SClient.DescribeUK8SCluster(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to describe k8s cluster %s: %v", clusterID, err)
+	}
+
+	if resp.ClusterId == "" {
+		return nil, nil, fmt.Errorf("k8s cluster %s not found", clusterID)
+	}
+
+	nodes, inScope, err := c.k8sNodesInScope(resp.ClusterId, instanceIDSet(instances))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !inScope {
+		return nil, nil, fmt.Errorf("k8s cluster %s not found", clusterID)
+	}
+
+	return resp, nodes, nil
+}
+
+// k8sNodesInScope gets the nodes of a cluster that are among the given instances in
+// scope, and reports whether the cluster is in scope: allow-listed or with such a node
+func (c *UCloudClient) k8sNodesInScope(clusterID string, instanceIDs map[string]bool) ([]uk8s.NodeInfoV2, bool, error) {
+	nodes, err := c.listK8SNodes(clusterID)
+	if err != nil || c.scope == nil {
+		return nodes, err == nil, err
+	}
+
+	nodes = filterK8SNodes(nodes, instanceIDs)
+	return nodes, len(nodes) > 0 || c.scope.ContainsResource(clusterID, ""), nil
+}
+
+// listK8SNodes gets all nodes of a UK8S cluster, regardless of the scope
+func (c *UCloudClient) listK8SNodes(clusterID string) ([]uk8s.NodeInfoV2, error) {
+	req := c.UK8SClient.NewListUK8SClusterNodeV2Request()
+	req.ClusterId = &clusterID
+
+	resp, err := c.UK8SClient.ListUK8SClusterNodeV2(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes of k8s cluster %s: %v", clusterID, err)
+	}
+	return resp.NodeSet, nil
+}
+
+// ListK8SNodeGroups gets the node groups of a UK8S cluster
+func (c *UCloudClient) ListK8SNodeGroups(clusterID string) ([]uk8s.NodeGroupSet, error) {
+	req := c.UK8SClient.NewListUK8SNodeGroupRequest()
+	req.ClusterId = &clusterID
+
+	resp, err := c.UK8SClient.ListUK8SNodeGroup(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node groups of k8s cluster %s: %v", clusterID, err)
+	}
+	return resp.NodeGroupList, nil
+}
+
+// instanceIDSet returns the IDs of the given instances
+func instanceIDSet(instances []uhost.UHostInstanceSet) map[string]bool {
+	instanceIDs := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		instanceIDs[instance.UHostId] = true
+	}
+	return instanceIDs
+}
+
+// filterK8SNodes returns the nodes backed by the given instances
+func filterK8SNodes(nodes []uk8s.NodeInfoV2, instanceIDs map[string]bool) []uk8s.NodeInfoV2 {
+	var filtered []uk8s.NodeInfoV2
+	for _, node := range nodes {
+		if instanceIDs[node.InstanceId] {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered
+}
+
+// K8SClusterInfo represents UK8S cluster information for API response
+type K8SClusterInfo struct {
+	ID                string `json:"id"`
+	ProjectID         string `json:"project_id,omitempty"`
+	Name              string `json:"name"`
+	Status            string `json:"status"`
+	Version           string `json:"version"`
+	APIServer         string `json:"api_server"`
+	ExternalAPIServer string `json:"external_api_server,omitempty"`
+	MasterCount       int    `json:"master_count"`
+	NodeCount         int    `json:"node_count"`
+	PodCIDR           string `json:"pod_cidr"`
+	ServiceCIDR       string `json:"service_cidr"`
+	VPCID             string `json:"vpc_id"`
+	SubnetID          string `json:"subnet_id"`
+	CreateTime        string `json:"create_time"`
+}
+
+// FormatK8SClusterInfo formats UK8S cluster list information for API response
+func FormatK8SClusterInfo(cluster *uk8s.ClusterSet) *K8SClusterInfo {
+	if cluster == nil {
+		return nil
+	}
+
+	return &K8SClusterInfo{
+		ID:                cluster.ClusterId,
+		Name:              cluster.ClusterName,
+		Status:            cluster.Status,
+		Version:           cluster.K8sVersion,
+		APIServer:         cluster.ApiServer,
+		ExternalAPIServer: cluster.ExternalApiServer,
+		MasterCount:       cluster.MasterCount,
+		NodeCount:         cluster.NodeCount,
+		PodCIDR:           cluster.PodCIDR,
+		ServiceCIDR:       cluster.ServiceCIDR,
+		VPCID:             cluster.VPCId,
+		SubnetID:          cluster.SubnetId,
+		CreateTime:        time.Unix(int64(cluster.CreateTime), 0).Format(time.RFC3339),
+	}
+}
+
+// FormatK8SClusterDetail formats a described UK8S cluster for API response
+func FormatK8SClusterDetail(cluster *uk8s.DescribeUK8SClusterResponse) *K8SClusterInfo {
+	if cluster == nil {
+		return nil
+	}
+
+	return &K8SClusterInfo{
+		ID:                cluster.ClusterId,
+		Name:              cluster.ClusterName,
+		Status:            cluster.Status,
+		Version:           cluster.Version,
+		APIServer:         cluster.ApiServer,
+		ExternalAPIServer: cluster.ExternalApiServer,
+		MasterCount:       cluster.MasterCount,
+		NodeCount:         cluster.NodeCount,
+		PodCIDR:           cluster.PodCIDR,
+		ServiceCIDR:       cluster.ServiceCIDR,
+		VPCID:             cluster.VPCId,
+		SubnetID:          cluster.SubnetId,
+		CreateTime:        time.Unix(int64(cluster.CreateTime), 0).Format(time.RFC3339),
+	}
+}
+
+// K8SNodeGroupInfo represents a UK8S node group for API response
+type K8SNodeGroupInfo struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	MachineType string   `json:"machine_type,omitempty"`
+	CPU         int      `json:"cpu"`
+	Memory      int      `json:"memory"`
+	GPU         int      `json:"gpu,omitempty"`
+	NodeIDs     []string `json:"node_ids"`
+}
+
+// FormatK8SNodeGroupInfo formats a UK8S node group for API response
+func FormatK8SNodeGroupInfo(group *uk8s.NodeGroupSet) *K8SNodeGroupInfo {
+	if group == nil {
+		return nil
+	}
+
+	return &K8SNodeGroupInfo{
+		ID:          group.NodeGroupId,
+		Name:        group.NodeGroupName,
+		MachineType: group.MachineType,
+		CPU:         group.CPU,
+		Memory:      group.Mem,
+		GPU:         group.GPU,
+		NodeIDs:     append([]string{}, group.NodeList...),
+	}
+}
+
+// K8SNodeInfo represents a UK8S node and the instance backing it for API response
+type K8SNodeInfo struct {
+	ID            string      `json:"id"`
+	Role          string      `json:"role"`
+	Status        string      `json:"status"`
+	Unschedulable bool        `json:"unschedulable"`
+	NodeGroupID   string      `json:"node_group_id,omitempty"`
+	InstanceID    string      `json:"instance_id"`
+	InstanceName  string      `json:"instance_name"`
+	InstanceType  string      `json:"instance_type"`
+	Zone          string      `json:"zone"`
+	IP            string      `json:"ip"`
+	CPU           int         `json:"cpu"`
+	Memory        int         `json:"memory"`
+	GPU           int         `json:"gpu,omitempty"`
+	OS            string      `json:"os"`
+	InstanceState string      `json:"instance_state,omitempty"`
+	Metrics       interface{} `json:"metrics,omitempty"`
+}
+
+// FormatK8SNodeInfo formats a UK8S node for API response
+func FormatK8SNodeInfo(node *uk8s.NodeInfoV2) *K8SNodeInfo {
+	if node == nil {
+		return nil
+	}
+
+	var ip string
+	if len(node.IPSet) > 0 {
+		ip = node.IPSet[0].IP
+	}
+
+	return &K8SNodeInfo{
+		ID:            node.NodeId,
+		Role:          node.NodeRole,
+		Status:        node.NodeStatus,
+		Unschedulable: node.Unschedulable,
+		InstanceID:    node.InstanceId,
+		InstanceName:  node.InstanceName,
+		InstanceType:  node.InstanceType,
+		Zone:          node.Zone,
+		IP:            ip,
+		CPU:           node.CPU,
+		Memory:        node.Memory,
+		GPU:           node.GPU,
+		OS:            node.OsName,
+	}
+}
@@ -0,0 +1,266 @@
+package ucloud
+
+import (
+	"fmt"
+
+	"github.com/ucloud/ucloud-sdk-go/services/ulb"
+	"github.com/ucloud/ucloud-sdk-go/ucloud"
+)
+
+// BackendTypeUHost is the ULB backend resource type of UHost instances
+const BackendTypeUHost = "UHost"
+
+// ListLoadBalancers gets the load balancers in the client's scope
+func (c *UCloudClient) ListLoadBalancers() ([]ulb.ULBSet, error) {
+	var allLoadBalancers []ulb.ULBSet
+	check := c.newScopeCheck()
+	limit := 100
+	offset := 0
+
+	for {
+		req := c.ULBClient.NewDescribeULBRequest()
+		req.Limit = &limit
+		req.Offset = &offset
+
+		resp, err := c.ULBClient.DescribeULB(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list load balancers: %v", err)
+		}
+
+		for _, lb := range resp.DataSet {
+			ok, err := check.contains(lb.ULBId, lb.Tag, backendHosts(&lb)...)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				allLoadBalancers = append(allLoadBalancers, lb)
+			}
+		}
+
+		// If the number of load balancers is less than limit, we've got all data
+		if len(resp.DataSet) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+	}
+
+	return allLoadBalancers, nil
+}
+
+// DescribeLoadBalancer gets a load balancer with its VServers and backends.
+// Load balancers outside the client's scope are reported as not found.
+func (c *UCloudClient) DescribeLoadBalancer(lbID string) (*ulb.ULBSet, error) {
+	req := c.ULBClient.NewDescribeULBRequest()
+	req.ULBId = &lbID
+
+	resp, err := c.ULBClient.DescribeULB(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe load balancer %s: %v", lbID, err)
+	}
+
+	if len(resp.DataSet) == 0 {
+		return nil, fmt.Errorf("load balancer %s not found", lbID)
+	}
+	lb := &resp.DataSet[0]
+	ok, err := c.newScopeCheck().contains(lb.ULBId, lb.Tag, backendHosts(lb)...)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("load balancer %s not found", lbID)
+	}
+
+	return lb, nil
+}
+
+// backendHosts returns the IDs of the instances behind a load balancer
+func backendHosts(lb *ulb.ULBSet) []string {
+	var hosts []string
+	for _, vserver := range lb.VServerSet {
+		for _, backend := range vserver.BackendSet {
+			if backend.ResourceType == BackendTypeUHost {
+				hosts = append(hosts, backend.ResourceId)
+			}
+		}
+	}
+	return hosts
+}
+
+// AddBackend adds an instance to a VServer and returns the backend ID
+func (c *UCloudClient) AddBackend(lbID, vserverID, hostID string, port, weight int) (string, error) {
+	req := c.ULBClient.NewAllocateBackendRequest()
+	req.ULBId = &lbID
+	req.VServerId = &vserverID
+	req.ResourceType = ucloud.String(BackendTypeUHost)
+	req.ResourceId = &hostID
+	req.Port = &port
+	req.Weight = &weight
+
+	resp, err := c.ULBClient.AllocateBackend(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to add %s to VServer %s: %v", hostID, vserverID, err)
+	}
+	return resp.BackendId, nil
+}
+
+// RemoveBackend removes a backend from its load balancer
+func (c *UCloudClient) RemoveBackend(lbID, backendID string) error {
+	req := c.ULBClient.NewReleaseBackendRequest()
+	req.ULBId = &lbID
+	req.BackendId = &backendID
+
+	if _, err := c.ULBClient.ReleaseBackend(req); err != nil {
+		return fmt.Errorf("failed to remove backend %s: %v", backendID, err)
+	}
+	return nil
+}
+
+// SetBackendWeight changes the weight of a backend
+func (c *UCloudClient) SetBackendWeight(lbID, backendID string, weight int) error {
+	req := c.ULBClient.NewUpdateBackendAttributeRequest()
+	req.ULBId = &lbID
+	req.BackendId = &backendID
+	req.Weight = &weight
+
+	if _, err := c.ULBClient.UpdateBackendAttribute(req); err != nil {
+		return fmt.Errorf("failed to set weight of backend %s: %v", backendID, err)
+	}
+	return nil
+}
+
+// SetBackendEnabled enables or disables a backend. A disabled backend stays
+// attached but receives no traffic.
+func (c *UCloudClient) SetBackendEnabled(lbID, backendID string, enabled bool) error {
+	req := c.ULBClient.NewUpdateBackendAttributeRequest()
+	req.ULBId = &lbID
+	req.BackendId = &backendID
+	if enabled {
+		req.Enabled = ucloud.Int(1)
+	} else {
+		req.Enabled = ucloud.Int(0)
+	}
+
+	if _, err := c.ULBClient.UpdateBackendAttribute(req); err != nil {
+		return fmt.Errorf("failed to update backend %s: %v", backendID, err)
+	}
+	return nil
+}
+
+// LoadBalancerInfo represents load balancer information for API response
+type LoadBalancerInfo struct {
+	ID         string        `json:"id"`
+	ProjectID  string        `json:"project_id,omitempty"`
+	Name       string        `json:"name"`
+	Type       string        `json:"type"`
+	ListenType string        `json:"listen_type,omitempty"`
+	PrivateIP  string        `json:"private_ip,omitempty"`
+	PublicIPs  []string      `json:"public_ips,omitempty"`
+	VPCID      string        `json:"vpc_id,omitempty"`
+	SubnetID   string        `json:"subnet_id,omitempty"`
+	Tag        string        `json:"tag,omitempty"`
+	VServers   []VServerInfo `json:"vservers"`
+}
+
+// VServerInfo represents a VServer, the listener of a load balancer
+type VServerInfo struct {
+	ID           string        `json:"id"`
+	Name         string        `json:"name"`
+	Protocol     string        `json:"protocol"`
+	FrontendPort int           `json:"frontend_port"`
+	Method       string        `json:"method"`
+	HealthCheck  string        `json:"health_check"`
+	Status       string        `json:"status"`
+	Backends     []BackendInfo `json:"backends"`
+
+	// HiddenBackends counts backends left out because the caller may not see them
+	HiddenBackends int `json:"hidden_backends,omitempty"`
+}
+
+// BackendInfo represents a load balancer backend with its health status
+type BackendInfo struct {
+	ID           string `json:"id"`
+	ResourceID   string `json:"resource_id"`
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name,omitempty"`
+	PrivateIP    string `json:"private_ip"`
+	Port         int    `json:"port"`
+	Weight       int    `json:"weight"`
+	Enabled      bool   `json:"enabled"`
+	Health       string `json:"health"`
+}
+
+// vserverStatus describes the health of the backends of a VServer
+func vserverStatus(status int) string {
+	switch status {
+	case 0:
+		return "healthy"
+	case 1:
+		return "unhealthy"
+	case 2:
+		return "partially_unhealthy"
+	}
+	return "unknown"
+}
+
+// FormatBackendInfo formats load balancer backend information for API response
+func FormatBackendInfo(backend *ulb.ULBBackendSet) BackendInfo {
+	health := "healthy"
+	if backend.Status != 0 {
+		health = "unhealthy"
+	}
+
+	return BackendInfo{
+		ID:           backend.BackendId,
+		ResourceID:   backend.ResourceId,
+		ResourceType: backend.ResourceType,
+		ResourceName: backend.ResourceName,
+		PrivateIP:    backend.PrivateIP,
+		Port:         backend.Port,
+		Weight:       backend.Weight,
+		Enabled:      backend.Enabled == 1,
+		Health:       health,
+	}
+}
+
+// FormatLoadBalancerInfo formats load balancer information for API response
+func FormatLoadBalancerInfo(lb *ulb.ULBSet) *LoadBalancerInfo {
+	if lb == nil {
+		return nil
+	}
+
+	info := &LoadBalancerInfo{
+		ID:         lb.ULBId,
+		Name:       lb.Name,
+		Type:       lb.ULBType,
+		ListenType: lb.ListenType,
+		PrivateIP:  lb.PrivateIP,
+		VPCID:      lb.VPCId,
+		SubnetID:   lb.SubnetId,
+		Tag:        lb.Tag,
+		VServers:   make([]VServerInfo, 0, len(lb.VServerSet)),
+	}
+	for _, ip := range lb.IPSet {
+		info.PublicIPs = append(info.PublicIPs, ip.EIP)
+	}
+
+	for _, vserver := range lb.VServerSet {
+		vs := VServerInfo{
+			ID:           vserver.VServerId,
+			Name:         vserver.VServerName,
+			Protocol:     vserver.Protocol,
+			FrontendPort: vserver.FrontendPort,
+			Method:       vserver.Method,
+			HealthCheck:  vserver.MonitorType,
+			Status:       vserverStatus(vserver.Status),
+			Backends:     make([]BackendInfo, 0, len(vserver.BackendSet)),
+		}
+		for i := range vserver.BackendSet {
+			vs.Backends = append(vs.Backends, FormatBackendInfo(&vserver.BackendSet[i]))
+		}
+		info.VServers = append(info.VServers, vs)
+	}
+
+	return info
+}
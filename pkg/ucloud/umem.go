@@ -0,0 +1,257 @@
+package ucloud
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ucloud/ucloud-sdk-go/services/umem"
+)
+
+const (
+	// CacheEngineRedis is the engine of URedis master-replica instances
+	CacheEngineRedis = "redis"
+
+	// CacheEngineMemcache is the engine of UMemcache instances
+	CacheEngineMemcache = "memcache"
+
+	// memcacheIDPrefix is the prefix of UMemcache instance IDs
+	memcacheIDPrefix = "umemcache-"
+)
+
+// cacheMetricResourceTypes maps cache engines to their monitoring resource types
+var cacheMetricResourceTypes = map[string]string{
+	CacheEngineRedis:    "uredis",
+	CacheEngineMemcache: "umemcache",
+}
+
+// ListCaches gets the Redis and Memcache instances in the client's scope
+func (c *UCloudClient) ListCaches() ([]*CacheInfo, error) {
+	var allCaches []*CacheInfo
+	limit := 100
+
+	for offset := 0; ; offset += limit {
+		req := c.UMemClient.NewDescribeURedisGroupRequest()
+		req.Limit = &limit
+		req.Offset = &offset
+
+		resp, err := c.UMemClient.DescribeURedisGroup(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list redis instances: %v", err)
+		}
+
+		for i := range resp.DataSet {
+			if c.scope.ContainsResource(resp.DataSet[i].GroupId, resp.DataSet[i].Tag) {
+				allCaches = append(allCaches, FormatRedisInfo(&resp.DataSet[i]))
+			}
+		}
+
+		// If the number of instances is less than limit, we've got all data
+		if len(resp.DataSet) < limit {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += limit {
+		req := c.UMemClient.NewDescribeUMemcacheGroupRequest()
+		req.Limit = &limit
+		req.Offset = &offset
+
+		resp, err := c.UMemClient.DescribeUMemcacheGroup(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list memcache instances: %v", err)
+		}
+
+		for i := range resp.DataSet {
+			if c.scope.ContainsResource(resp.DataSet[i].GroupId, resp.DataSet[i].Tag) {
+				allCaches = append(allCaches, FormatMemcacheInfo(&resp.DataSet[i]))
+			}
+		}
+
+		// If the number of instances is less than limit, we've got all data
+		if len(resp.DataSet) < limit {
+			break
+		}
+	}
+
+	return allCaches, nil
+}
+
+// DescribeCache gets a Redis or Memcache instance, depending on the prefix of its ID.
+// Instances outside the client's scope are reported as not found.
+func (c *UCloudClient) DescribeCache(cacheID string) (*CacheInfo, error) {
+	var info *CacheInfo
+	if strings.HasPrefix(cacheID, memcacheIDPrefix) {
+		req := c.UMemClient.NewDescribeUMemcacheGroupRequest()
+		req.GroupId = &cacheID
+
+		resp, err := c.UMemClient.DescribeUMemcacheGroup(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe cache instance %s: %v", cacheID, err)
+		}
+		if len(resp.DataSet) > 0 {
+			info = FormatMemcacheInfo(&resp.DataSet[0])
+		}
+	} else {
+		req := c.UMemClient.NewDescribeURedisGroupRequest()
+		req.GroupId = &cacheID
+
+		resp, err := c.UMemClient.DescribeURedisGroup(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe cache instance %s: %v", cacheID, err)
+		}
+		if len(resp.DataSet) > 0 {
+			info = FormatRedisInfo(&resp.DataSet[0])
+		}
+	}
+
+	if info == nil || !c.scope.ContainsResource(info.ID, info.Tag) {
+		return nil, fmt.Errorf("cache instance %s not found", cacheID)
+	}
+
+	return info, nil
+}
+
+// GetCacheMetrics retrieves the latest monitoring metrics of a cache instance
+func (c *UCloudClient) GetCacheMetrics(cache *CacheInfo) (ResourceMetrics, error) {
+	if cache == nil {
+		return nil, fmt.Errorf("cache instance is nil")
+	}
+	return c.GetResourceMetrics(cache.Zone, cacheMetricResourceTypes[cache.Engine], cache.ID)
+}
+
+// RedisSizesGB are the memory sizes a Redis instance can be resized to
+var RedisSizesGB = []int{1, 2, 4, 8, 16, 32}
+
+// CheckRedisResize returns an error unless a Redis instance can be resized to sizeGB.
+// The UMem API only grows instances, and only to one of RedisSizesGB.
+func CheckRedisResize(cache *CacheInfo, sizeGB int) error {
+	if sizeGB <= cache.Size {
+		return fmt.Errorf("cache instance %s is %d GB and can only grow, not be resized to %d GB",
+			cache.ID, cache.Size, sizeGB)
+	}
+	for _, size := range RedisSizesGB {
+		if size == sizeGB {
+			return nil
+		}
+	}
+	return fmt.Errorf("size must be one of %v GB, got %d", RedisSizesGB, sizeGB)
+}
+
+// redisSpaceType returns the space type of a Redis instance, "single" or "double" (with a hot standby)
+func redisSpaceType(group *umem.URedisGroupSet) string {
+	if group.Type != "" {
+		return group.Type
+	}
+	if group.HighAvailability == "disable" {
+		return "single"
+	}
+	return "double"
+}
+
+// ResizeCache grows a Redis instance to a new memory size in GB, keeping its space type.
+// The UMem API does not support resizing Memcache instances.
+func (c *UCloudClient) ResizeCache(cache *CacheInfo, sizeGB int) error {
+	if cache.Engine != CacheEngineRedis {
+		return fmt.Errorf("resizing %s instances is not supported", cache.Engine)
+	}
+	if err := CheckRedisResize(cache, sizeGB); err != nil {
+		return err
+	}
+
+	req := c.UMemClient.NewResizeURedisGroupRequest()
+	req.Zone = &cache.Zone
+	req.GroupId = &cache.ID
+	req.Size = &sizeGB
+	// The API defaults to "double", which would change single instances
+	if cache.SpaceType != "" {
+		req.Type = &cache.SpaceType
+	}
+
+	if _, err := c.UMemClient.ResizeURedisGroup(req); err != nil {
+		return fmt.Errorf("failed to resize cache instance %s: %v", cache.ID, err)
+	}
+	return nil
+}
+
+// CacheInfo represents Redis or Memcache instance information for API response
+type CacheInfo struct {
+	ID               string `json:"id"`
+	ProjectID        string `json:"project_id,omitempty"`
+	Name             string `json:"name"`
+	Engine           string `json:"engine"`
+	Version          string `json:"version"`
+	Status           string `json:"status"`
+	Zone             string `json:"zone,omitempty"`
+	SlaveZone        string `json:"slave_zone,omitempty"`
+	HighAvailability string `json:"high_availability,omitempty"`
+	SpaceType        string `json:"space_type,omitempty"`
+	Role             string `json:"role,omitempty"`
+	Address          string `json:"address"`
+	Size             int    `json:"size"`
+	UsedMB           int    `json:"used_mb"`
+	MemoryUsage      string `json:"memory_usage"`
+	VPCID            string `json:"vpc_id,omitempty"`
+	SubnetID         string `json:"subnet_id,omitempty"`
+	Tag              string `json:"tag,omitempty"`
+	CreateTime       string `json:"create_time"`
+}
+
+// FormatRedisInfo formats URedis instance information for API response
+func FormatRedisInfo(group *umem.URedisGroupSet) *CacheInfo {
+	if group == nil {
+		return nil
+	}
+
+	return &CacheInfo{
+		ID:               group.GroupId,
+		Name:             group.Name,
+		Engine:           CacheEngineRedis,
+		Version:          group.Version,
+		Status:           group.State,
+		Zone:             group.Zone,
+		SlaveZone:        group.SlaveZone,
+		HighAvailability: group.HighAvailability,
+		SpaceType:        redisSpaceType(group),
+		Role:             group.Role,
+		Address:          fmt.Sprintf("%s:%d", group.VirtualIP, group.Port),
+		Size:             group.Size,
+		UsedMB:           group.UsedSize,
+		MemoryUsage:      memoryUsage(group.UsedSize, group.Size),
+		VPCID:            group.VPCId,
+		SubnetID:         group.SubnetId,
+		Tag:              group.Tag,
+		CreateTime:       time.Unix(int64(group.CreateTime), 0).Format(time.RFC3339),
+	}
+}
+
+// FormatMemcacheInfo formats UMemcache instance information for API response
+func FormatMemcacheInfo(group *umem.UMemcacheGroupSet) *CacheInfo {
+	if group == nil {
+		return nil
+	}
+
+	return &CacheInfo{
+		ID:          group.GroupId,
+		Name:        group.Name,
+		Engine:      CacheEngineMemcache,
+		Version:     group.Version,
+		Status:      group.State,
+		Address:     fmt.Sprintf("%s:%d", group.VirtualIP, group.Port),
+		Size:        group.Size,
+		UsedMB:      group.UsedSize,
+		MemoryUsage: memoryUsage(group.UsedSize, group.Size),
+		VPCID:       group.VPCId,
+		SubnetID:    group.SubnetId,
+		Tag:         group.Tag,
+		CreateTime:  time.Unix(int64(group.CreateTime), 0).Format(time.RFC3339),
+	}
+}
+
+// memoryUsage formats used MB of a cache of the given size in GB as a percentage
+func memoryUsage(usedMB, sizeGB int) string {
+	if sizeGB <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f%%", float64(usedMB)/float64(sizeGB*1024)*100)
+}
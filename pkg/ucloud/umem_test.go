@@ -0,0 +1,69 @@
+package ucloud
+
+import (
+	"testing"
+
+	"github.com/ucloud/ucloud-sdk-go/services/umem"
+)
+
+func TestMemoryUsage(t *testing.T) {
+	tests := []struct {
+		name   string
+		usedMB int
+		sizeGB int
+		want   string
+	}{
+		{"empty", 0, 1, "0.0%"},
+		{"half", 512, 1, "50.0%"},
+		{"several GB", 3072, 4, "75.0%"},
+		{"unknown size", 100, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := memoryUsage(tt.usedMB, tt.sizeGB); got != tt.want {
+				t.Errorf("memoryUsage(%d, %d) = %q, want %q", tt.usedMB, tt.sizeGB, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRedisResize(t *testing.T) {
+	cache := &CacheInfo{ID: "uredis-a", Size: 4}
+
+	tests := []struct {
+		size    int
+		wantErr bool
+	}{
+		{8, false},
+		{32, false},
+		{4, true},  // same size
+		{2, true},  // shrinking
+		{12, true}, // not a supported size
+		{64, true}, // above the largest size
+	}
+
+	for _, tt := range tests {
+		if err := CheckRedisResize(cache, tt.size); (err != nil) != tt.wantErr {
+			t.Errorf("CheckRedisResize(%d) = %v, want error %v", tt.size, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRedisSpaceType(t *testing.T) {
+	tests := []struct {
+		group umem.URedisGroupSet
+		want  string
+	}{
+		{umem.URedisGroupSet{Type: "single"}, "single"},
+		{umem.URedisGroupSet{Type: "double", HighAvailability: "disable"}, "double"},
+		{umem.URedisGroupSet{HighAvailability: "disable"}, "single"},
+		{umem.URedisGroupSet{HighAvailability: "enable"}, "double"},
+	}
+
+	for _, tt := range tests {
+		if got := redisSpaceType(&tt.group); got != tt.want {
+			t.Errorf("redisSpaceType(%+v) = %q, want %q", tt.group, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package ucloud
+
+import (
+	"fmt"
+
+	"github.com/ucloud/ucloud-sdk-go/services/vpc"
+)
+
+// SubnetResourceUHost is the subnet resource type of UHost instances
+const SubnetResourceUHost = "UHOST"
+
+// ListVPCs gets the VPCs in the client's scope
+func (c *UCloudClient) ListVPCs() ([]vpc.VPCInfo, error) {
+	var allVPCs []vpc.VPCInfo
+	check := c.newScopeCheck()
+	limit := 100
+	offset := 0
+
+	for {
+		req := c.VPCClient.NewDescribeVPCRequest()
+		req.Limit = &limit
+		req.Offset = &offset
+
+		resp, err := c.VPCClient.DescribeVPC(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list VPCs: %v", err)
+		}
+
+		for _, v := range resp.DataSet {
+			ok, err := check.containsNetwork(v.VPCId, v.Tag)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				allVPCs = append(allVPCs, v)
+			}
+		}
+
+		// If the number of VPCs is less than limit, we've got all data
+		if len(resp.DataSet) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+	}
+
+	return allVPCs, nil
+}
+
+// DescribeVPC gets detailed information about a VPC.
+// VPCs outside the client's scope are reported as not found.
+func (c *UCloudClient) DescribeVPC(vpcID string) (*vpc.VPCInfo, error) {
+	req := c.VPCClient.NewDescribeVPCRequest()
+	req.VPCIds = []string{vpcID}
+
+	resp, err := c.VPCClient.DescribeVPC(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe VPC %s: %v", vpcID, err)
+	}
+
+	if len(resp.DataSet) == 0 {
+		return nil, fmt.Errorf("VPC %s not found", vpcID)
+	}
+	ok, err := c.newScopeCheck().containsNetwork(resp.DataSet[0].VPCId, resp.DataSet[0].Tag)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("VPC %s not found", vpcID)
+	}
+
+	return &resp.DataSet[0], nil
+}
+
+// ListSubnets gets the subnets in the client's scope, optionally only those of a VPC
+func (c *UCloudClient) ListSubnets(vpcID string) ([]vpc.SubnetInfo, error) {
+	var allSubnets []vpc.SubnetInfo
+	check := c.newScopeCheck()
+	limit := 100
+	offset := 0
+	showAvailableIPs := true
+
+	for {
+		req := c.VPCClient.NewDescribeSubnetRequest()
+		req.Limit = &limit
+		req.Offset = &offset
+		req.ShowAvailableIPs = &showAvailableIPs
+		if vpcID != "" {
+			req.VPCId = &vpcID
+		}
+
+		resp, err := c.VPCClient.DescribeSubnet(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list subnets: %v", err)
+		}
+
+		for _, subnet := range resp.DataSet {
+			ok, err := check.containsNetwork(subnet.SubnetId, subnet.Tag)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				allSubnets = append(allSubnets, subnet)
+			}
+		}
+
+		// If the number of subnets is less than limit, we've got all data
+		if len(resp.DataSet) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+	}
+
+	return allSubnets, nil
+}
+
+// SubnetResources gets the resources placed in a subnet
+func (c *UCloudClient) SubnetResources(subnetID string) ([]vpc.SubnetResource, error) {
+	var allResources []vpc.SubnetResource
+	limit := 100
+	offset := 0
+
+	for {
+		req := c.VPCClient.NewDescribeSubnetResourceRequest()
+		req.SubnetId = &subnetID
+		req.Limit = &limit
+		req.Offset = &offset
+
+		resp, err := c.VPCClient.DescribeSubnetResource(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resources of subnet %s: %v", subnetID, err)
+		}
+		allResources = append(allResources, resp.DataSet...)
+
+		// If the number of resources is less than limit, we've got all data
+		if len(resp.DataSet) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+	}
+
+	return allResources, nil
+}
+
+// ListRouteTables gets the route tables of a VPC with their rules
+func (c *UCloudClient) ListRouteTables(vpcID string) ([]vpc.RouteTableInfo, error) {
+	var allTables []vpc.RouteTableInfo
+	limit := 100
+	offset := 0
+
+	for {
+		req := c.VPCClient.NewDescribeRouteTableRequest()
+		req.VPCId = &vpcID
+		req.Limit = &limit
+		req.OffSet = &offset
+
+		resp, err := c.VPCClient.DescribeRouteTable(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list route tables of VPC %s: %v", vpcID, err)
+		}
+		allTables = append(allTables, resp.RouteTables...)
+
+		// If the number of route tables is less than limit, we've got all data
+		if len(resp.RouteTables) < limit {
+			break
+		}
+
+		// Update offset for next page
+		offset += limit
+	}
+
+	return allTables, nil
+}
+
+// VPCInfo represents VPC information for API response
+type VPCInfo struct {
+	ID          string   `json:"id"`
+	ProjectID   string   `json:"project_id,omitempty"`
+	Name        string   `json:"name"`
+	CIDRs       []string `json:"cidrs"`
+	IPv6CIDR    string   `json:"ipv6_cidr,omitempty"`
+	Type        string   `json:"type"`
+	Tag         string   `json:"tag,omitempty"`
+	SubnetCount int      `json:"subnet_count"`
+}
+
+// FormatVPCInfo formats VPC information for API response
+func FormatVPCInfo(v *vpc.VPCInfo) *VPCInfo {
+	if v == nil {
+		return nil
+	}
+
+	return &VPCInfo{
+		ID:          v.VPCId,
+		Name:        v.Name,
+		CIDRs:       v.Network,
+		IPv6CIDR:    v.IPv6Network,
+		Type:        v.VPCType,
+		Tag:         v.Tag,
+		SubnetCount: v.SubnetCount,
+	}
+}
+
+// SubnetInfo represents subnet information for API response
+type SubnetInfo struct {
+	ID           string `json:"id"`
+	ProjectID    string `json:"project_id,omitempty"`
+	Name         string `json:"name"`
+	VPCID        string `json:"vpc_id"`
+	CIDR         string `json:"cidr"`
+	Gateway      string `json:"gateway"`
+	Zone         string `json:"zone,omitempty"`
+	RouteTableID string `json:"route_table_id,omitempty"`
+	AvailableIPs int    `json:"available_ips"`
+	HasNATGW     bool   `json:"has_natgw"`
+	Tag          string `json:"tag,omitempty"`
+}
+
+// FormatSubnetInfo formats subnet information for API response
+func FormatSubnetInfo(subnet *vpc.SubnetInfo) *SubnetInfo {
+	if subnet == nil {
+		return nil
+	}
+
+	return &SubnetInfo{
+		ID:           subnet.SubnetId,
+		Name:         subnet.SubnetName,
+		VPCID:        subnet.VPCId,
+		CIDR:         fmt.Sprintf("%s/%s", subnet.Subnet, subnet.Netmask),
+		Gateway:      subnet.Gateway,
+		Zone:         subnet.Zone,
+		RouteTableID: subnet.RouteTableId,
+		AvailableIPs: subnet.AvailableIPs,
+		HasNATGW:     subnet.HasNATGW,
+		Tag:          subnet.Tag,
+	}
+}
+
+// RouteInfo is a single route of a route table
+type RouteInfo struct {
+	Destination string `json:"destination"`
+	NextHopType string `json:"next_hop_type"`
+	NextHopID   string `json:"next_hop_id,omitempty"`
+	Custom      bool   `json:"custom"`
+	Remark      string `json:"remark,omitempty"`
+}
+
+// RouteTableInfo represents route table information for API response
+type RouteTableInfo struct {
+	ID        string      `json:"id"`
+	Default   bool        `json:"default"`
+	SubnetIDs []string    `json:"subnet_ids"`
+	Routes    []RouteInfo `json:"routes"`
+}
+
+// FormatRouteTableInfo formats route table information for API response
+func FormatRouteTableInfo(table *vpc.RouteTableInfo) *RouteTableInfo {
+	if table == nil {
+		return nil
+	}
+
+	routes := make([]RouteInfo, 0, len(table.RouteRules))
+	for _, rule := range table.RouteRules {
+		routes = append(routes, RouteInfo{
+			Destination: rule.DstAddr,
+			NextHopType: rule.NexthopType,
+			NextHopID:   rule.NexthopId,
+			Custom:      rule.RuleType == 1,
+			Remark:      rule.Remark,
+		})
+	}
+
+	return &RouteTableInfo{
+		ID:        table.RouteTableId,
+		Default:   table.RouteTableType == 1,
+		SubnetIDs: table.SubnetIds,
+		Routes:    routes,
+	}
+}
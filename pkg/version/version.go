@@ -0,0 +1,10 @@
+package version
+
+// Version is the version of the UCloud MCP server.
+// It can be overridden at build time with -ldflags "-X github.com/ucloud/ucloud-mcp-server/pkg/version.Version=x.y.z".
+var Version = "1.0.0"
+
+// UserAgent returns the user agent token identifying the server in UCloud API requests
+func UserAgent() string {
+	return "UCloud-MCP-Server/" + Version
+}